@@ -0,0 +1,200 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// day of calendar
+type date struct {
+	y int
+	m time.Month
+	d int
+}
+
+// day of year of calendar, repeated every year
+type yearDay struct {
+	m time.Month
+	d int
+}
+
+// calendar of excluded dates: weekdays, single dates and dates repeated every year, e.g. weekends and public holidays;
+// dates are taken in location of checked time, so task's calendar works in task's location
+type Calendar struct {
+	sync.RWMutex
+	weekdays map[time.Weekday]bool
+	dates    map[date]string
+	yearly   map[yearDay]string
+}
+
+// creates empty calendar
+func New() *Calendar {
+	return &Calendar{weekdays: make(map[time.Weekday]bool), dates: make(map[date]string), yearly: make(map[yearDay]string)}
+}
+
+// creates calendar of business days excluding Saturday, Sunday and holidays
+func BusinessDays(holidays ...*Calendar) *Calendar {
+	c := New()
+	c.ExcludeWeekdays(time.Saturday, time.Sunday)
+
+	for _, h := range holidays {
+		c.Merge(h)
+	}
+
+	return c
+}
+
+// excludes weekdays
+func (c *Calendar) ExcludeWeekdays(days ...time.Weekday) error {
+	for _, wd := range days {
+		if wd < time.Sunday || wd > time.Saturday {
+			return fmt.Errorf("Weekday %v is not valid", wd)
+		}
+	}
+
+	defer c.Unlock()
+	c.Lock()
+
+	for _, wd := range days {
+		c.weekdays[wd] = true
+	}
+
+	return nil
+}
+
+// excludes date named name
+func (c *Calendar) ExcludeDate(y int, m time.Month, d int, name string) error {
+	if !valid(y, m, d) {
+		return fmt.Errorf("Date %04d-%02d-%02d is not valid", y, m, d)
+	}
+
+	defer c.Unlock()
+	c.Lock()
+
+	c.dates[date{y, m, d}] = name
+
+	return nil
+}
+
+// excludes dates from till to inclusive named name
+func (c *Calendar) ExcludeRange(from time.Time, to time.Time, name string) error {
+	fy, fm, fd := from.Date()
+	ty, tm, td := to.Date()
+	first := time.Date(fy, fm, fd, 12, 0, 0, 0, time.UTC)
+	last := time.Date(ty, tm, td, 12, 0, 0, 0, time.UTC)
+
+	if from.IsZero() || last.Before(first) {
+		return fmt.Errorf("Range %v-%v is not valid", from, to)
+	}
+
+	defer c.Unlock()
+	c.Lock()
+
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		y, m, d := day.Date()
+		c.dates[date{y, m, d}] = name
+	}
+
+	return nil
+}
+
+// excludes day of every year named name, e.g. Christmas; February 29 is excluded in leap years only
+func (c *Calendar) ExcludeYearly(m time.Month, d int, name string) error {
+	if !valid(2024, m, d) {
+		return fmt.Errorf("Day %02d-%02d is not valid", m, d)
+	}
+
+	defer c.Unlock()
+	c.Lock()
+
+	c.yearly[yearDay{m, d}] = name
+
+	return nil
+}
+
+// adds excluded weekdays and dates of other calendar
+func (c *Calendar) Merge(other *Calendar) {
+	if other == nil || other == c {
+		return
+	}
+
+	other.RLock()
+	weekdays, dates, yearly := make([]time.Weekday, 0, len(other.weekdays)), make(map[date]string, len(other.dates)), make(map[yearDay]string, len(other.yearly))
+	for wd := range other.weekdays {
+		weekdays = append(weekdays, wd)
+	}
+	for k, v := range other.dates {
+		dates[k] = v
+	}
+	for k, v := range other.yearly {
+		yearly[k] = v
+	}
+	other.RUnlock()
+
+	defer c.Unlock()
+	c.Lock()
+
+	for _, wd := range weekdays {
+		c.weekdays[wd] = true
+	}
+	for k, v := range dates {
+		c.dates[k] = v
+	}
+	for k, v := range yearly {
+		c.yearly[k] = v
+	}
+}
+
+// checks if date of tm in its location is excluded
+func (c *Calendar) Excludes(tm time.Time) bool {
+	_, ok := c.Holiday(tm)
+
+	return ok
+}
+
+// returns name of excluded date of tm in its location, excluded weekday is named by weekday
+func (c *Calendar) Holiday(tm time.Time) (string, bool) {
+	defer c.RUnlock()
+	c.RLock()
+
+	y, m, d := tm.Date()
+
+	if name, ok := c.dates[date{y, m, d}]; ok {
+		return name, true
+	}
+
+	if name, ok := c.yearly[yearDay{m, d}]; ok {
+		return name, true
+	}
+
+	if c.weekdays[tm.Weekday()] {
+		return tm.Weekday().String(), true
+	}
+
+	return "", false
+}
+
+// returns string representation of calendar
+func (c *Calendar) String() string {
+	defer c.RUnlock()
+	c.RLock()
+
+	weekdays := []time.Weekday{}
+	for wd := range c.weekdays {
+		weekdays = append(weekdays, wd)
+	}
+	sort.Slice(weekdays, func(i, k int) bool {
+		return weekdays[i] < weekdays[k]
+	})
+
+	return fmt.Sprintf("excluded weekdays %v, %d dates, %d yearly days", weekdays, len(c.dates), len(c.yearly))
+}
+
+// checks if date exists
+func valid(y int, m time.Month, d int) bool {
+	tm := time.Date(y, m, d, 12, 0, 0, 0, time.UTC)
+
+	return tm.Year() == y && tm.Month() == m && tm.Day() == d
+}
@@ -0,0 +1,64 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_Calendar_Excludes(t *testing.T) {
+	holidays := New()
+
+	if err := holidays.ExcludeYearly(time.December, 25, "Christmas"); err != nil {
+		t.Fatal("Failed to exclude day: ", err)
+	}
+
+	if err := holidays.ExcludeDate(2024, time.July, 5, "Bridge day"); err != nil {
+		t.Fatal("Failed to exclude date: ", err)
+	}
+
+	if err := holidays.ExcludeRange(time.Date(2024, 8, 12, 0, 0, 0, 0, time.UTC), time.Date(2024, 8, 14, 0, 0, 0, 0, time.UTC), "Shutdown"); err != nil {
+		t.Fatal("Failed to exclude range: ", err)
+	}
+
+	c := BusinessDays(holidays)
+
+	cases := []struct {
+		tm   time.Time
+		name string
+	}{
+		{time.Date(2024, 7, 4, 12, 0, 0, 0, time.UTC), ""},
+		{time.Date(2024, 7, 5, 12, 0, 0, 0, time.UTC), "Bridge day"},
+		{time.Date(2024, 7, 6, 12, 0, 0, 0, time.UTC), "Saturday"},
+		{time.Date(2025, 12, 25, 0, 0, 0, 0, time.UTC), "Christmas"},
+		{time.Date(2024, 8, 13, 23, 59, 0, 0, time.UTC), "Shutdown"},
+		{time.Date(2024, 8, 15, 0, 0, 0, 0, time.UTC), ""},
+		// date is taken in location of time
+		{time.Date(2024, 7, 4, 23, 0, 0, 0, time.UTC).In(time.FixedZone("UTC+2", 2*3600)), "Bridge day"},
+	}
+
+	for _, c2 := range cases {
+		if name, ok := c.Holiday(c2.tm); name != c2.name || ok != (c2.name != "") || c.Excludes(c2.tm) != ok {
+			t.Error("Wrong exclusion of ", c2.tm, ": ", name, ok)
+		}
+	}
+
+	if holidays.Excludes(time.Date(2024, 7, 6, 12, 0, 0, 0, time.UTC)) {
+		t.Error("Business days changed merged calendar")
+	}
+
+	if err := c.ExcludeDate(2023, time.February, 29, ""); err == nil {
+		t.Error("Failed to detect wrong date")
+	}
+
+	if err := c.ExcludeYearly(time.April, 31, ""); err == nil {
+		t.Error("Failed to detect wrong day")
+	}
+
+	if err := c.ExcludeWeekdays(time.Weekday(7)); err == nil {
+		t.Error("Failed to detect wrong weekday")
+	}
+
+	if err := c.ExcludeRange(time.Now(), time.Now().AddDate(0, 0, -1), ""); err == nil {
+		t.Error("Failed to detect wrong range")
+	}
+}
@@ -0,0 +1,233 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// property of iCalendar component
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// creates calendar excluding dates of events of iCalendar data
+func ParseICS(r io.Reader) (*Calendar, error) {
+	c := New()
+
+	if err := c.ImportICS(r); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// creates calendar excluding dates of events of iCalendar file
+func LoadICS(path string) (*Calendar, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open calendar: %v", err)
+	}
+	defer f.Close()
+
+	return ParseICS(f)
+}
+
+// excludes dates of events of iCalendar data, events may recur yearly; calendar is left unchanged if any event fails
+func (c *Calendar) ImportICS(r io.Reader) error {
+	lines, err := unfold(r)
+	if err != nil {
+		return err
+	}
+
+	var event []property
+	events := [][]property{}
+	for i, line := range lines {
+		p, err := parseProperty(line)
+		if err != nil {
+			return fmt.Errorf("Failed to parse line %d of calendar: %v", i+1, err)
+		}
+
+		switch {
+		case p.name == "BEGIN" && strings.EqualFold(p.value, "VEVENT"):
+			event = []property{}
+		case p.name == "END" && strings.EqualFold(p.value, "VEVENT"):
+			if event == nil {
+				return fmt.Errorf("Failed to parse line %d of calendar: event is not started", i+1)
+			}
+
+			events = append(events, event)
+			event = nil
+		case event != nil:
+			event = append(event, p)
+		}
+	}
+
+	if event != nil {
+		return fmt.Errorf("Failed to parse calendar: event is not finished")
+	}
+
+	// events are imported to empty calendar first, so calendar is left unchanged if any of them fails
+	imported := New()
+	for _, e := range events {
+		if err := imported.importEvent(e); err != nil {
+			return err
+		}
+	}
+
+	c.Merge(imported)
+
+	return nil
+}
+
+// excludes dates of event
+func (c *Calendar) importEvent(e []property) error {
+	var start, end time.Time
+	var name string
+	var yearly, allDay bool
+
+	for _, p := range e {
+		var err error
+
+		switch p.name {
+		case "SUMMARY":
+			name = unescape(p.value)
+		case "DTSTART":
+			start, allDay, err = parseTime(p)
+		case "DTEND":
+			end, _, err = parseTime(p)
+		case "RRULE":
+			yearly, err = parseRule(p.value)
+		}
+
+		if err != nil {
+			return fmt.Errorf("Failed to parse event %q: %v", name, err)
+		}
+	}
+
+	if start.IsZero() {
+		return fmt.Errorf("Failed to parse event %q: no start", name)
+	}
+
+	// end of event is exclusive, all-day event without end lasts one day
+	last := start
+	if !end.IsZero() {
+		last = end.Add(-time.Nanosecond)
+		if allDay || last.Before(start) {
+			last = end.AddDate(0, 0, -1)
+		}
+		if last.Before(start) {
+			last = start
+		}
+	}
+
+	if !yearly {
+		return c.ExcludeRange(start, last, name)
+	}
+
+	y, m, d := start.Date()
+	for day := time.Date(y, m, d, 12, 0, 0, 0, time.UTC); !day.After(time.Date(last.Year(), last.Month(), last.Day(), 12, 0, 0, 0, time.UTC)); day = day.AddDate(0, 0, 1) {
+		if err := c.ExcludeYearly(day.Month(), day.Day(), name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// returns lines of iCalendar data joined with their continuation lines
+func unfold(r io.Reader) ([]string, error) {
+	lines := []string{}
+	s := bufio.NewScanner(r)
+
+	for s.Scan() {
+		line := strings.TrimRight(s.Text(), "\r")
+
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("Failed to read calendar: %v", err)
+	}
+
+	return lines, nil
+}
+
+// parses content line NAME;PARAM=VALUE:VALUE
+func parseProperty(line string) (property, error) {
+	i := strings.Index(line, ":")
+	if i < 0 {
+		return property{}, fmt.Errorf("no value in %q", line)
+	}
+
+	parts := strings.Split(line[:i], ";")
+	p := property{name: strings.ToUpper(parts[0]), params: make(map[string]string), value: line[i+1:]}
+
+	for _, param := range parts[1:] {
+		k, v, _ := strings.Cut(param, "=")
+		p.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+
+	return p, nil
+}
+
+// parses date or date-time value of property, date is returned as midnight of UTC
+func parseTime(p property) (time.Time, bool, error) {
+	if p.params["VALUE"] == "DATE" || len(p.value) == len("20060102") {
+		tm, err := time.Parse("20060102", p.value)
+
+		return tm, true, err
+	}
+
+	loc := time.UTC
+	if tzid, ok := p.params["TZID"]; ok {
+		l, err := time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		loc = l
+	}
+
+	if strings.HasSuffix(p.value, "Z") {
+		tm, err := time.Parse("20060102T150405Z", p.value)
+
+		return tm, false, err
+	}
+
+	tm, err := time.ParseInLocation("20060102T150405", p.value, loc)
+
+	return tm, false, err
+}
+
+// parses recurrence rule, returns if event recurs yearly; other rules aren't supported
+func parseRule(rule string) (bool, error) {
+	for _, part := range strings.Split(rule, ";") {
+		k, v, _ := strings.Cut(strings.ToUpper(part), "=")
+
+		switch {
+		case k == "FREQ" && v == "YEARLY":
+		case k == "INTERVAL" && v == "1":
+		default:
+			return false, fmt.Errorf("recurrence rule %q is not supported", rule)
+		}
+	}
+
+	return true, nil
+}
+
+// unescapes text value
+func unescape(s string) string {
+	return strings.NewReplacer(`\\`, `\`, `\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ").Replace(s)
+}
@@ -0,0 +1,89 @@
+package calendar
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const holidaysICS = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Test//Holidays//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:1@test\r\n" +
+	"DTSTART;VALUE=DATE:20240101\r\n" +
+	"DTEND;VALUE=DATE:20240102\r\n" +
+	"RRULE:FREQ=YEARLY\r\n" +
+	"SUMMARY:New Year\\, Day\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:2@test\r\n" +
+	"DTSTART;VALUE=DATE:20240329\r\n" +
+	"DTEND;VALUE=DATE:20240402\r\n" +
+	"SUMMARY:Easter\r\n" +
+	"  holidays\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:3@test\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20240501T090000\r\n" +
+	"DTEND;TZID=Europe/Berlin:20240502T000000\r\n" +
+	"SUMMARY:Labour Day\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func Test_Calendar_ParseICS(t *testing.T) {
+	c, err := ParseICS(strings.NewReader(holidaysICS))
+	if err != nil {
+		t.Fatal("Failed to parse calendar: ", err)
+	}
+
+	cases := []struct {
+		tm   time.Time
+		name string
+	}{
+		{time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC), "New Year, Day"},
+		{time.Date(2030, 1, 2, 12, 0, 0, 0, time.UTC), ""},
+		{time.Date(2024, 3, 28, 12, 0, 0, 0, time.UTC), ""},
+		{time.Date(2024, 3, 29, 12, 0, 0, 0, time.UTC), "Easter holidays"},
+		{time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC), "Easter holidays"},
+		{time.Date(2024, 4, 2, 12, 0, 0, 0, time.UTC), ""},
+		{time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC), ""},
+		{time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), "Labour Day"},
+		{time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC), ""},
+	}
+
+	for _, c2 := range cases {
+		if name, _ := c.Holiday(c2.tm); name != c2.name {
+			t.Error("Wrong holiday of ", c2.tm, ": ", name)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "holidays.ics")
+	if err := os.WriteFile(path, []byte(holidaysICS), 0644); err != nil {
+		t.Fatal("Failed to write calendar: ", err)
+	}
+
+	if c, err := LoadICS(path); err != nil || !c.Excludes(time.Date(2024, 3, 30, 12, 0, 0, 0, time.UTC)) {
+		t.Error("Failed to load calendar: ", err)
+	}
+
+	broken := []string{
+		"BEGIN:VEVENT\r\nSUMMARY:No start\r\nEND:VEVENT\r\n",
+		"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20240101\r\n",
+		"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:2024-01-01\r\nEND:VEVENT\r\n",
+		"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20240101\r\nRRULE:FREQ=WEEKLY;BYDAY=MO\r\nEND:VEVENT\r\n",
+		"BEGIN:VEVENT\r\nbroken line\r\nEND:VEVENT\r\n",
+	}
+
+	for _, data := range broken {
+		if _, err := ParseICS(strings.NewReader(data)); err == nil {
+			t.Error("Failed to detect broken calendar: ", data)
+		}
+	}
+	partial := "BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20240102\r\nEND:VEVENT\r\n" + broken[3]
+	if err := c.ImportICS(strings.NewReader(partial)); err == nil || c.Excludes(time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)) {
+		t.Error("Failed to leave calendar unchanged on broken event: ", err)
+	}
+}
@@ -0,0 +1,141 @@
+package lock
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// locker based on exclusive locks of files in directory, shares keys between processes of one host;
+// lock of file is held until lease is released or process exits, ttl only sets expiry of lease
+type FileLocker struct {
+	sync.Mutex
+	dir    string
+	owner  string
+	files  map[string]*os.File
+	leases map[string]Lease
+}
+
+// creates file locker keeping lock files in existing directory dir
+func NewFileLocker(dir string, owner string) (*FileLocker, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open lock directory: %v", err)
+	}
+
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%v is not a directory", dir)
+	}
+
+	if owner == "" {
+		return nil, fmt.Errorf("No owner provided")
+	}
+
+	return &FileLocker{dir: dir, owner: owner, files: make(map[string]*os.File), leases: make(map[string]Lease)}, nil
+}
+
+// acquires lock of file key.lock, token of lease is kept in file
+func (l *FileLocker) Acquire(ctx context.Context, key string, ttl time.Duration) (Lease, error) {
+	if err := check(key, ttl); err != nil {
+		return Lease{}, err
+	}
+
+	defer l.Unlock()
+	l.Lock()
+
+	if _, ok := l.files[key]; ok {
+		return Lease{}, ErrLocked
+	}
+
+	f, err := os.OpenFile(filepath.Join(l.dir, key+".lock"), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return Lease{}, fmt.Errorf("Failed to open lock file: %v", err)
+	}
+
+	if err := flock(f); err != nil {
+		f.Close()
+		return Lease{}, err
+	}
+
+	token, err := bump(f, l.owner)
+	if err != nil {
+		funlock(f)
+		f.Close()
+		return Lease{}, err
+	}
+
+	lease := Lease{Key: key, Owner: l.owner, Token: token, Expires: time.Now().Add(ttl)}
+	l.files[key] = f
+	l.leases[key] = lease
+
+	return lease, nil
+}
+
+// extends lease if its file is still locked by locker
+func (l *FileLocker) Renew(ctx context.Context, lease Lease, ttl time.Duration) (Lease, error) {
+	if err := check(lease.Key, ttl); err != nil {
+		return Lease{}, err
+	}
+
+	defer l.Unlock()
+	l.Lock()
+
+	if held, ok := l.leases[lease.Key]; !ok || held.Token != lease.Token {
+		return Lease{}, ErrLeaseLost
+	}
+
+	lease.Expires = time.Now().Add(ttl)
+	l.leases[lease.Key] = lease
+
+	return lease, nil
+}
+
+// unlocks file of lease
+func (l *FileLocker) Release(ctx context.Context, lease Lease) error {
+	defer l.Unlock()
+	l.Lock()
+
+	if held, ok := l.leases[lease.Key]; !ok || held.Token != lease.Token {
+		return ErrLeaseLost
+	}
+
+	f := l.files[lease.Key]
+	delete(l.files, lease.Key)
+	delete(l.leases, lease.Key)
+
+	funlock(f)
+
+	return f.Close()
+}
+
+// increments token kept in locked file f and writes owner next to it, returns new token
+func bump(f *os.File, owner string) (int64, error) {
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to read lock file: %v", err)
+	}
+
+	token := int64(0)
+	if fields := strings.Fields(string(data)); len(fields) > 0 {
+		if token, err = strconv.ParseInt(fields[0], 10, 64); err != nil {
+			return 0, fmt.Errorf("Lock file is corrupted: %v", err)
+		}
+	}
+	token++
+
+	if err := f.Truncate(0); err != nil {
+		return 0, fmt.Errorf("Failed to write lock file: %v", err)
+	}
+
+	if _, err := f.WriteAt([]byte(fmt.Sprintf("%d %v\n", token, owner)), 0); err != nil {
+		return 0, fmt.Errorf("Failed to write lock file: %v", err)
+	}
+
+	return token, nil
+}
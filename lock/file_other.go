@@ -0,0 +1,17 @@
+//go:build !unix
+
+package lock
+
+import (
+	"fmt"
+	"os"
+)
+
+// file locks aren't supported
+func flock(f *os.File) error {
+	return fmt.Errorf("File locks are not supported on this platform")
+}
+
+// file locks aren't supported
+func funlock(f *os.File) {
+}
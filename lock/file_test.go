@@ -0,0 +1,60 @@
+//go:build unix
+
+package lock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_File_AcquireRelease(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	a, err := NewFileLocker(dir, "a")
+	if err != nil {
+		t.Fatal("Failed to create locker: ", err)
+	}
+
+	b, _ := NewFileLocker(dir, "b")
+
+	la, err := a.Acquire(ctx, "job", time.Second)
+	if err != nil || la.Token != 1 || la.Owner != "a" {
+		t.Fatal("Failed to acquire lease: ", la, err)
+	}
+
+	if _, err := b.Acquire(ctx, "job", time.Second); !errors.Is(err, ErrLocked) {
+		t.Error("Failed to detect held lock: ", err)
+	}
+
+	if _, err := a.Acquire(ctx, "job", time.Second); !errors.Is(err, ErrLocked) {
+		t.Error("Failed to detect lock held by the same locker: ", err)
+	}
+
+	if la, err = a.Renew(ctx, la, time.Minute); err != nil || time.Until(la.Expires) < time.Second*30 {
+		t.Error("Failed to renew lease: ", la, err)
+	}
+
+	if err := a.Release(ctx, la); err != nil {
+		t.Error("Failed to release lease: ", err)
+	}
+
+	if _, err := a.Renew(ctx, la, time.Second); !errors.Is(err, ErrLeaseLost) {
+		t.Error("Failed to detect released lease: ", err)
+	}
+
+	lb, err := b.Acquire(ctx, "job", time.Second)
+	if err != nil || lb.Token != 2 {
+		t.Error("Failed to take over lease with greater token: ", lb, err)
+	}
+
+	if _, err := a.Acquire(ctx, "../job", time.Second); err == nil {
+		t.Error("Failed to detect key with path")
+	}
+
+	if _, err := NewFileLocker(dir+"/none", "a"); err == nil {
+		t.Error("Failed to detect missing directory")
+	}
+}
@@ -0,0 +1,29 @@
+//go:build unix
+
+package lock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// locks file f exclusively without waiting, returns ErrLocked if it's locked by another open file
+func flock(f *os.File) error {
+	err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return ErrLocked
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed to lock file: %v", err)
+	}
+
+	return nil
+}
+
+// unlocks file f
+func funlock(f *os.File) {
+	syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
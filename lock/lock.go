@@ -0,0 +1,136 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errors of lockers
+var (
+	ErrLocked    = errors.New("Lock is held by another owner")
+	ErrLeaseLost = errors.New("Lease is lost")
+)
+
+// lease of lock held by owner, token grows with every acquisition of key and fences writes of stale owners
+type Lease struct {
+	Key     string
+	Owner   string
+	Token   int64
+	Expires time.Time
+}
+
+// acquires, renews and releases leases of keys shared by replicas
+type Locker interface {
+	// acquires lease of key for ttl, returns ErrLocked if key is held by another owner
+	Acquire(ctx context.Context, key string, ttl time.Duration) (Lease, error)
+	// extends lease for ttl, returns ErrLeaseLost if lease isn't held anymore
+	Renew(ctx context.Context, lease Lease, ttl time.Duration) (Lease, error)
+	// releases lease, returns ErrLeaseLost if lease isn't held anymore
+	Release(ctx context.Context, lease Lease) error
+}
+
+// checks if key and ttl of lease are valid
+func check(key string, ttl time.Duration) error {
+	if key == "" || strings.ContainsAny(key, `/\`) || key == "." || key == ".." {
+		return fmt.Errorf("Key %q is not valid", key)
+	}
+
+	if ttl <= 0 {
+		return fmt.Errorf("TTL must be greater than 0")
+	}
+
+	return nil
+}
+
+// returns interval of renewal of lease for ttl, third of it but at least 1ns
+func RenewInterval(ttl time.Duration) time.Duration {
+	if ttl < 3 {
+		return 1
+	}
+
+	return ttl / 3
+}
+
+// holds leadership of key for replica while it's running
+type Leader struct {
+	sync.Mutex
+	locker Locker
+	key    string
+	ttl    time.Duration
+	lease  Lease
+	held   bool
+}
+
+// creates leader campaigning for key with leases for ttl
+func NewLeader(l Locker, key string, ttl time.Duration) (*Leader, error) {
+	if l == nil {
+		return nil, fmt.Errorf("No locker provided")
+	}
+
+	if err := check(key, ttl); err != nil {
+		return nil, err
+	}
+
+	return &Leader{locker: l, key: key, ttl: ttl}, nil
+}
+
+// campaigns for leadership and renews its lease every third of ttl until ctx is done, then releases lease
+func (ld *Leader) Run(ctx context.Context) {
+	ticker := time.NewTicker(RenewInterval(ld.ttl))
+	defer ticker.Stop()
+
+	for {
+		ld.campaign(ctx)
+
+		select {
+		case <-ctx.Done():
+			ld.Lock()
+			if ld.held {
+				ld.locker.Release(context.Background(), ld.lease)
+				ld.held = false
+			}
+			ld.Unlock()
+
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// acquires lease if it isn't held or renews it
+func (ld *Leader) campaign(ctx context.Context) {
+	ld.Lock()
+	held, lease := ld.held, ld.lease
+	ld.Unlock()
+
+	var err error
+	if held {
+		lease, err = ld.locker.Renew(ctx, lease, ld.ttl)
+	} else {
+		lease, err = ld.locker.Acquire(ctx, ld.key, ld.ttl)
+	}
+
+	defer ld.Unlock()
+	ld.Lock()
+
+	ld.held = err == nil
+	if ld.held {
+		ld.lease = lease
+	}
+}
+
+// returns lease of leadership and true if replica is leader
+func (ld *Leader) Lease() (Lease, bool) {
+	defer ld.Unlock()
+	ld.Lock()
+
+	if !ld.held || !time.Now().Before(ld.lease.Expires) {
+		return Lease{}, false
+	}
+
+	return ld.lease, true
+}
@@ -0,0 +1,121 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// locker keeping leases in memory
+type memLocker struct {
+	sync.Mutex
+	leases map[string]Lease
+	tokens map[string]int64
+}
+
+func newMemLocker() *memLocker {
+	return &memLocker{leases: make(map[string]Lease), tokens: make(map[string]int64)}
+}
+
+func (l *memLocker) Acquire(ctx context.Context, key string, ttl time.Duration) (Lease, error) {
+	defer l.Unlock()
+	l.Lock()
+
+	if held, ok := l.leases[key]; ok && time.Now().Before(held.Expires) {
+		return Lease{}, ErrLocked
+	}
+
+	l.tokens[key]++
+	l.leases[key] = Lease{Key: key, Token: l.tokens[key], Expires: time.Now().Add(ttl)}
+
+	return l.leases[key], nil
+}
+
+func (l *memLocker) Renew(ctx context.Context, lease Lease, ttl time.Duration) (Lease, error) {
+	defer l.Unlock()
+	l.Lock()
+
+	if held, ok := l.leases[lease.Key]; !ok || held.Token != lease.Token {
+		return Lease{}, ErrLeaseLost
+	}
+
+	lease.Expires = time.Now().Add(ttl)
+	l.leases[lease.Key] = lease
+
+	return lease, nil
+}
+
+func (l *memLocker) Release(ctx context.Context, lease Lease) error {
+	defer l.Unlock()
+	l.Lock()
+
+	if held, ok := l.leases[lease.Key]; !ok || held.Token != lease.Token {
+		return ErrLeaseLost
+	}
+
+	delete(l.leases, lease.Key)
+
+	return nil
+}
+
+func Test_Leader_Run(t *testing.T) {
+	l := newMemLocker()
+
+	a, err := NewLeader(l, "leader", time.Millisecond*30)
+	if err != nil {
+		t.Fatal("Failed to create leader: ", err)
+	}
+
+	b, _ := NewLeader(l, "leader", time.Millisecond*30)
+
+	ctxA, cancelA := context.WithCancel(context.Background())
+	ctxB, cancelB := context.WithCancel(context.Background())
+	defer cancelB()
+
+	go a.Run(ctxA)
+	time.Sleep(time.Millisecond * 10)
+	go b.Run(ctxB)
+	time.Sleep(time.Millisecond * 50)
+
+	la, okA := a.Lease()
+	_, okB := b.Lease()
+
+	if !okA || okB || la.Token != 1 {
+		t.Error("Wrong leadership: ", okA, okB, la)
+	}
+
+	cancelA()
+	time.Sleep(time.Millisecond * 50)
+
+	lb, okB := b.Lease()
+	if _, okA := a.Lease(); okA || !okB || lb.Token != 2 {
+		t.Error("Failed to take over leadership: ", okA, okB, lb)
+	}
+
+	if _, err := NewLeader(nil, "leader", time.Second); err == nil {
+		t.Error("Failed to detect missing locker")
+	}
+
+	if _, err := NewLeader(l, "leader", 0); err == nil {
+		t.Error("Failed to detect wrong ttl")
+	}
+
+	if err := l.Release(context.Background(), la); !errors.Is(err, ErrLeaseLost) {
+		t.Error("Failed to detect released lease: ", err)
+	}
+	tiny, err := NewLeader(l, "tiny", 1)
+	if err != nil {
+		t.Fatal("Failed to create leader with tiny ttl: ", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+
+	tiny.Run(ctx)
+
+	if RenewInterval(1) != 1 || RenewInterval(time.Second*3) != time.Second {
+		t.Error("Wrong interval of renewal: ", RenewInterval(1), RenewInterval(time.Second*3))
+	}
+}
@@ -0,0 +1,129 @@
+package lock
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// locker based on leases kept in table of SQL database, shares keys between hosts;
+// queries use ? placeholders as SQLite and MySQL do, expiry of leases is compared by clocks of replicas
+type SQLLocker struct {
+	db    *sql.DB
+	table string
+	owner string
+}
+
+// creates SQL locker keeping leases in table
+func NewSQLLocker(db *sql.DB, table string, owner string) (*SQLLocker, error) {
+	if db == nil {
+		return nil, fmt.Errorf("No database provided")
+	}
+
+	if !identifier.MatchString(table) {
+		return nil, fmt.Errorf("Table name %q is not valid", table)
+	}
+
+	if owner == "" {
+		return nil, fmt.Errorf("No owner provided")
+	}
+
+	return &SQLLocker{db: db, table: table, owner: owner}, nil
+}
+
+// creates table of leases if it doesn't exist
+func (l *SQLLocker) CreateTable(ctx context.Context) error {
+	_, err := l.db.ExecContext(ctx, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %v (name VARCHAR(255) PRIMARY KEY, owner VARCHAR(255) NOT NULL, token BIGINT NOT NULL, expires BIGINT NOT NULL)", l.table))
+	if err != nil {
+		return fmt.Errorf("Failed to create table of leases: %v", err)
+	}
+
+	return nil
+}
+
+// acquires lease of key if it's free or expired, row of key is kept after release to keep its token growing
+func (l *SQLLocker) Acquire(ctx context.Context, key string, ttl time.Duration) (Lease, error) {
+	if err := check(key, ttl); err != nil {
+		return Lease{}, err
+	}
+
+	now := time.Now()
+	lease := Lease{Key: key, Owner: l.owner, Token: 1, Expires: now.Add(ttl)}
+
+	var token, expires int64
+	err := l.db.QueryRowContext(ctx, fmt.Sprintf("SELECT token, expires FROM %v WHERE name = ?", l.table), key).Scan(&token, &expires)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		_, err = l.db.ExecContext(ctx, fmt.Sprintf("INSERT INTO %v (name, owner, token, expires) VALUES (?, ?, ?, ?)", l.table), key, l.owner, lease.Token, lease.Expires.UnixNano())
+		if err != nil {
+			// key is locked only if another owner has inserted its row meanwhile
+			if e := l.db.QueryRowContext(ctx, fmt.Sprintf("SELECT token, expires FROM %v WHERE name = ?", l.table), key).Scan(&token, &expires); e == nil {
+				return Lease{}, ErrLocked
+			}
+
+			return Lease{}, fmt.Errorf("Failed to insert lease: %v", err)
+		}
+
+		return lease, nil
+	}
+
+	if err != nil {
+		return Lease{}, fmt.Errorf("Failed to read lease: %v", err)
+	}
+
+	if expires > now.UnixNano() {
+		return Lease{}, ErrLocked
+	}
+
+	lease.Token = token + 1
+	res, err := l.db.ExecContext(ctx, fmt.Sprintf("UPDATE %v SET owner = ?, token = ?, expires = ? WHERE name = ? AND token = ?", l.table), l.owner, lease.Token, lease.Expires.UnixNano(), key, token)
+
+	return lease, l.changed(res, err, ErrLocked)
+}
+
+// extends lease if it isn't expired and its token hasn't changed
+func (l *SQLLocker) Renew(ctx context.Context, lease Lease, ttl time.Duration) (Lease, error) {
+	if err := check(lease.Key, ttl); err != nil {
+		return Lease{}, err
+	}
+
+	now := time.Now()
+	res, err := l.db.ExecContext(ctx, fmt.Sprintf("UPDATE %v SET expires = ? WHERE name = ? AND owner = ? AND token = ? AND expires > ?", l.table), now.Add(ttl).UnixNano(), lease.Key, l.owner, lease.Token, now.UnixNano())
+	if err := l.changed(res, err, ErrLeaseLost); err != nil {
+		return Lease{}, err
+	}
+
+	lease.Expires = now.Add(ttl)
+
+	return lease, nil
+}
+
+// expires lease if its token hasn't changed
+func (l *SQLLocker) Release(ctx context.Context, lease Lease) error {
+	res, err := l.db.ExecContext(ctx, fmt.Sprintf("UPDATE %v SET expires = 0 WHERE name = ? AND owner = ? AND token = ?", l.table), lease.Key, l.owner, lease.Token)
+
+	return l.changed(res, err, ErrLeaseLost)
+}
+
+// returns error of query or lost if query hasn't changed any row
+func (l *SQLLocker) changed(res sql.Result, err error, lost error) error {
+	if err != nil {
+		return fmt.Errorf("Failed to update lease: %v", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Failed to update lease: %v", err)
+	}
+
+	if n == 0 {
+		return lost
+	}
+
+	return nil
+}
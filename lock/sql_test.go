@@ -0,0 +1,246 @@
+package lock
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// row of table of leases
+type leaseRow struct {
+	owner   string
+	token   int64
+	expires int64
+}
+
+// driver of database understanding only queries of SQLLocker, keeps tables in memory
+type leaseDriver struct {
+	sync.Mutex
+	rows map[string]leaseRow
+	fail error // returned by inserts if set
+}
+
+var leases = &leaseDriver{rows: make(map[string]leaseRow)}
+
+func init() {
+	sql.Register("leases", leases)
+}
+
+func (d *leaseDriver) Open(name string) (driver.Conn, error) {
+	return leaseConn{d}, nil
+}
+
+type leaseConn struct {
+	d *leaseDriver
+}
+
+func (c leaseConn) Prepare(query string) (driver.Stmt, error) {
+	return leaseStmt{d: c.d, query: query}, nil
+}
+
+func (c leaseConn) Close() error {
+	return nil
+}
+
+func (c leaseConn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("Transactions are not supported")
+}
+
+type leaseStmt struct {
+	d     *leaseDriver
+	query string
+}
+
+func (s leaseStmt) Close() error {
+	return nil
+}
+
+func (s leaseStmt) NumInput() int {
+	return -1
+}
+
+func (s leaseStmt) Exec(args []driver.Value) (driver.Result, error) {
+	defer s.d.Unlock()
+	s.d.Lock()
+
+	q := s.query
+	switch {
+	case strings.HasPrefix(q, "CREATE TABLE"):
+		return driver.RowsAffected(0), nil
+	case strings.HasPrefix(q, "INSERT"):
+		if s.d.fail != nil {
+			return nil, s.d.fail
+		}
+
+		name := args[0].(string)
+		if _, ok := s.d.rows[name]; ok {
+			return nil, fmt.Errorf("UNIQUE constraint failed")
+		}
+
+		s.d.rows[name] = leaseRow{owner: args[1].(string), token: args[2].(int64), expires: args[3].(int64)}
+
+		return driver.RowsAffected(1), nil
+	case strings.Contains(q, "SET owner = ?"):
+		r, ok := s.d.rows[args[3].(string)]
+		if !ok || r.token != args[4].(int64) {
+			return driver.RowsAffected(0), nil
+		}
+
+		s.d.rows[args[3].(string)] = leaseRow{owner: args[0].(string), token: args[1].(int64), expires: args[2].(int64)}
+
+		return driver.RowsAffected(1), nil
+	case strings.Contains(q, "SET expires = ?"):
+		r, ok := s.d.rows[args[1].(string)]
+		if !ok || r.owner != args[2].(string) || r.token != args[3].(int64) || r.expires <= args[4].(int64) {
+			return driver.RowsAffected(0), nil
+		}
+
+		r.expires = args[0].(int64)
+		s.d.rows[args[1].(string)] = r
+
+		return driver.RowsAffected(1), nil
+	case strings.Contains(q, "SET expires = 0"):
+		r, ok := s.d.rows[args[0].(string)]
+		if !ok || r.owner != args[1].(string) || r.token != args[2].(int64) {
+			return driver.RowsAffected(0), nil
+		}
+
+		r.expires = 0
+		s.d.rows[args[0].(string)] = r
+
+		return driver.RowsAffected(1), nil
+	}
+
+	return nil, fmt.Errorf("Query %q is not supported", q)
+}
+
+func (s leaseStmt) Query(args []driver.Value) (driver.Rows, error) {
+	defer s.d.Unlock()
+	s.d.Lock()
+
+	if !strings.HasPrefix(s.query, "SELECT token, expires") {
+		return nil, fmt.Errorf("Query %q is not supported", s.query)
+	}
+
+	r, ok := s.d.rows[args[0].(string)]
+	if !ok {
+		return &leaseRows{}, nil
+	}
+
+	return &leaseRows{values: [][]driver.Value{{r.token, r.expires}}}, nil
+}
+
+type leaseRows struct {
+	values [][]driver.Value
+}
+
+func (r *leaseRows) Columns() []string {
+	return []string{"token", "expires"}
+}
+
+func (r *leaseRows) Close() error {
+	return nil
+}
+
+func (r *leaseRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+
+	return nil
+}
+
+func Test_SQL_AcquireRenewRelease(t *testing.T) {
+	leases.Lock()
+	leases.rows = make(map[string]leaseRow)
+	leases.Unlock()
+
+	db, err := sql.Open("leases", "")
+	if err != nil {
+		t.Fatal("Failed to open database: ", err)
+	}
+	defer db.Close()
+
+	testSQLLocker(t, db)
+
+	leases.Lock()
+	leases.fail = fmt.Errorf("disk I/O error")
+	leases.Unlock()
+
+	defer func() {
+		leases.Lock()
+		leases.fail = nil
+		leases.Unlock()
+	}()
+
+	a, _ := NewSQLLocker(db, "leases", "a")
+	if _, err := a.Acquire(context.Background(), "other", time.Second); err == nil || errors.Is(err, ErrLocked) {
+		t.Error("Failed to return error of database: ", err)
+	}
+}
+
+// checks acquisition, renewal, release and fencing of leases of SQL lockers sharing db
+func testSQLLocker(t *testing.T, db *sql.DB) {
+	ctx := context.Background()
+
+	a, err := NewSQLLocker(db, "leases", "a")
+	if err != nil {
+		t.Fatal("Failed to create locker: ", err)
+	}
+
+	b, _ := NewSQLLocker(db, "leases", "b")
+
+	if err := a.CreateTable(ctx); err != nil {
+		t.Fatal("Failed to create table: ", err)
+	}
+
+	la, err := a.Acquire(ctx, "job", time.Millisecond*50)
+	if err != nil || la.Token != 1 {
+		t.Fatal("Failed to acquire lease: ", la, err)
+	}
+
+	if _, err := b.Acquire(ctx, "job", time.Second); !errors.Is(err, ErrLocked) {
+		t.Error("Failed to detect held lease: ", err)
+	}
+
+	if la, err = a.Renew(ctx, la, time.Millisecond*50); err != nil {
+		t.Error("Failed to renew lease: ", err)
+	}
+
+	time.Sleep(time.Millisecond * 60)
+
+	if _, err := a.Renew(ctx, la, time.Second); !errors.Is(err, ErrLeaseLost) {
+		t.Error("Failed to detect expired lease: ", err)
+	}
+
+	lb, err := b.Acquire(ctx, "job", time.Second)
+	if err != nil || lb.Token != 2 || lb.Owner != "b" {
+		t.Error("Failed to take over expired lease: ", lb, err)
+	}
+
+	if err := a.Release(ctx, la); !errors.Is(err, ErrLeaseLost) {
+		t.Error("Failed to fence stale lease: ", err)
+	}
+
+	if err := b.Release(ctx, lb); err != nil {
+		t.Error("Failed to release lease: ", err)
+	}
+
+	if la, err = a.Acquire(ctx, "job", time.Second); err != nil || la.Token != 3 {
+		t.Error("Failed to acquire released lease: ", la, err)
+	}
+
+	if _, err := NewSQLLocker(db, "leases; DROP", "a"); err == nil {
+		t.Error("Failed to detect wrong table name")
+	}
+}
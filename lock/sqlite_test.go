@@ -0,0 +1,73 @@
+//go:build sqlite
+
+package lock
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// opens SQLite database in temporary directory of test, tests of this file are run with -tags sqlite and need modernc.org/sqlite driver
+func openSQLite(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "leases.db")+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		t.Fatal("Failed to open database: ", err)
+	}
+
+	return db
+}
+
+func Test_SQLite_AcquireRenewRelease(t *testing.T) {
+	db := openSQLite(t)
+	defer db.Close()
+
+	testSQLLocker(t, db)
+}
+
+func Test_SQLite_Contention(t *testing.T) {
+	db := openSQLite(t)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	l, _ := NewSQLLocker(db, "leases", "a")
+	if err := l.CreateTable(ctx); err != nil {
+		t.Fatal("Failed to create table: ", err)
+	}
+
+	mu := sync.Mutex{}
+	acquired := 0
+	wg := sync.WaitGroup{}
+
+	for _, owner := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		wg.Add(1)
+		go func(owner string) {
+			defer wg.Done()
+
+			l, _ := NewSQLLocker(db, "leases", owner)
+			_, err := l.Acquire(ctx, "job", time.Second)
+
+			switch {
+			case err == nil:
+				mu.Lock()
+				acquired++
+				mu.Unlock()
+			case !errors.Is(err, ErrLocked):
+				t.Error("Failed to acquire lease: ", err)
+			}
+		}(owner)
+	}
+
+	wg.Wait()
+
+	if acquired != 1 {
+		t.Error("Lease is acquired by several owners: ", acquired)
+	}
+}
@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vslchnk/goscheduler/worker"
+)
+
+// errors of alerts which aren't sent
+var (
+	ErrDuplicate   = errors.New("Alert is duplicate")
+	ErrRateLimited = errors.New("Alert is rate limited")
+)
+
+// alert about event of job
+type Alert struct {
+	// alerts with the same key are duplicates
+	Key     string
+	Job     string
+	Event   worker.EventType
+	Outcome worker.Outcome
+	RunID   string
+	Time    time.Time
+	Message string
+	Labels  map[string]string
+}
+
+// sends alerts
+type Notifier interface {
+	// sends alert, returns error if alert isn't delivered
+	Notify(ctx context.Context, a Alert) error
+}
+
+// creates alert about event of job
+func NewAlert(e worker.Event) Alert {
+	return Alert{
+		Key:     fmt.Sprintf("%v/%v/%v", e.Job, e.Type, e.Run.Outcome),
+		Job:     e.Job,
+		Event:   e.Type,
+		Outcome: e.Run.Outcome,
+		RunID:   e.Run.RunID,
+		Time:    e.Time,
+		Message: e.Message,
+		Labels:  e.Labels,
+	}
+}
+
+// returns subject of alert
+func (a Alert) Subject() string {
+	if a.Outcome == "" {
+		return fmt.Sprintf("Job %v: %v", a.Job, a.Event)
+	}
+
+	return fmt.Sprintf("Job %v: %v, %v", a.Job, a.Event, a.Outcome)
+}
+
+// returns text of alert
+func (a Alert) String() string {
+	keys := make([]string, 0, len(a.Labels))
+	for k := range a.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	labels := make([]string, 0, len(keys))
+	for _, k := range keys {
+		labels = append(labels, k+"="+a.Labels[k])
+	}
+
+	return fmt.Sprintf("Job: %v\nEvent: %v\nOutcome: %v\nRun: %v\nTime: %v\nLabels: %v\nMessage: %v\n", a.Job, a.Event, a.Outcome, a.RunID, a.Time, strings.Join(labels, ","), a.Message)
+}
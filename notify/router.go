@@ -0,0 +1,204 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vslchnk/goscheduler/worker"
+)
+
+// default time limit of sending alert by router handling events
+const DefaultTimeout = time.Second * 10
+
+// rule routing alerts of jobs matching selector to notifiers
+type Rule struct {
+	Selector worker.Selector
+	// types of events routed by rule, any type if empty
+	Events []worker.EventType
+	// outcomes of runs routed by rule, any outcome if empty
+	Outcomes  []worker.Outcome
+	Notifiers []Notifier
+}
+
+// checks if rule routes alert
+func (r Rule) matches(a Alert) bool {
+	if !r.Selector.Matches(a.Labels) {
+		return false
+	}
+
+	if len(r.Events) > 0 && !contains(r.Events, a.Event) {
+		return false
+	}
+
+	return len(r.Outcomes) == 0 || contains(r.Outcomes, a.Outcome)
+}
+
+// checks if list contains value
+func contains[T comparable](list []T, value T) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// routes alerts to notifiers by rules, suppresses duplicate alerts and limits rate of alerts
+type Router struct {
+	sync.Mutex
+	rules   []Rule
+	dedup   time.Duration
+	rate    int
+	per     time.Duration
+	tokens  float64
+	filled  time.Time
+	sent    map[string]time.Time
+	timeout time.Duration
+	onError func(Alert, error)
+}
+
+// creates router suppressing alerts with the same key sent within dedup and sending at most rate alerts per per,
+// zero dedup disables de-duplication and zero rate disables rate limiting
+func NewRouter(dedup time.Duration, rate int, per time.Duration) (*Router, error) {
+	if dedup < 0 {
+		return nil, fmt.Errorf("De-duplication window is less than 0: %v", dedup)
+	}
+
+	if rate < 0 || (rate > 0 && per <= 0) {
+		return nil, fmt.Errorf("Rate %v per %v is not valid", rate, per)
+	}
+
+	return &Router{dedup: dedup, rate: rate, per: per, tokens: float64(rate), sent: make(map[string]time.Time), timeout: DefaultTimeout}, nil
+}
+
+// adds rule of router, alert is sent to notifiers of every matching rule
+func (r *Router) AddRule(rule Rule) error {
+	if len(rule.Notifiers) == 0 {
+		return fmt.Errorf("No notifiers provided")
+	}
+
+	for _, n := range rule.Notifiers {
+		if n == nil {
+			return fmt.Errorf("No notifier provided")
+		}
+	}
+
+	defer r.Unlock()
+	r.Lock()
+
+	r.rules = append(r.rules, rule)
+
+	return nil
+}
+
+// sets time limit of sending alert by Handle
+func (r *Router) SetTimeout(timeout time.Duration) error {
+	if timeout <= 0 {
+		return fmt.Errorf("Timeout must be greater than 0")
+	}
+
+	defer r.Unlock()
+	r.Lock()
+
+	r.timeout = timeout
+
+	return nil
+}
+
+// sets handler of errors of alerts sent by Handle
+func (r *Router) SetErrorHandler(handler func(Alert, error)) {
+	defer r.Unlock()
+	r.Lock()
+
+	r.onError = handler
+}
+
+// sends alert to notifiers of matching rules, returns ErrDuplicate or ErrRateLimited if alert is suppressed
+// and errors of notifiers which failed
+func (r *Router) Route(ctx context.Context, a Alert) error {
+	notifiers, err := r.admit(a, time.Now())
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, n := range notifiers {
+		if err := n.Notify(ctx, a); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// sends alert of event of job in background, so router can subscribe to events of worker
+func (r *Router) Handle(e worker.Event) {
+	a := NewAlert(e)
+
+	r.Lock()
+	timeout, onError := r.timeout, r.onError
+	r.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		err := r.Route(ctx, a)
+		if err != nil && !errors.Is(err, ErrDuplicate) && !errors.Is(err, ErrRateLimited) && onError != nil {
+			onError(a, err)
+		}
+	}()
+}
+
+// returns notifiers of rules matching alert at now, or error if alert is suppressed
+func (r *Router) admit(a Alert, now time.Time) ([]Notifier, error) {
+	defer r.Unlock()
+	r.Lock()
+
+	notifiers := []Notifier{}
+	for _, rule := range r.rules {
+		if !rule.matches(a) {
+			continue
+		}
+
+		notifiers = append(notifiers, rule.Notifiers...)
+	}
+
+	if len(notifiers) == 0 {
+		return nil, nil
+	}
+
+	if last, ok := r.sent[a.Key]; ok && r.dedup > 0 && now.Sub(last) < r.dedup {
+		return nil, ErrDuplicate
+	}
+
+	if r.rate > 0 {
+		if !r.filled.IsZero() {
+			r.tokens += float64(r.rate) * float64(now.Sub(r.filled)) / float64(r.per)
+			if r.tokens > float64(r.rate) {
+				r.tokens = float64(r.rate)
+			}
+		}
+		r.filled = now
+
+		if r.tokens < 1 {
+			return nil, ErrRateLimited
+		}
+		r.tokens--
+	}
+
+	if r.dedup > 0 {
+		r.sent[a.Key] = now
+		for k, tm := range r.sent {
+			if now.Sub(tm) >= r.dedup {
+				delete(r.sent, k)
+			}
+		}
+	}
+
+	return notifiers, nil
+}
@@ -0,0 +1,152 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	tk "github.com/vslchnk/goscheduler/task"
+	"github.com/vslchnk/goscheduler/worker"
+)
+
+// notifier recording alerts
+type recorder struct {
+	sync.Mutex
+	alerts []Alert
+	err    error
+}
+
+func (r *recorder) Notify(ctx context.Context, a Alert) error {
+	defer r.Unlock()
+	r.Lock()
+
+	r.alerts = append(r.alerts, a)
+
+	return r.err
+}
+
+func (r *recorder) count() int {
+	defer r.Unlock()
+	r.Lock()
+
+	return len(r.alerts)
+}
+
+func Test_Router_Route(t *testing.T) {
+	finance, ops := &recorder{}, &recorder{err: fmt.Errorf("Ops are down")}
+
+	r, err := NewRouter(time.Minute, 0, 0)
+	if err != nil {
+		t.Fatal("Failed to create router: ", err)
+	}
+
+	sel, _ := worker.ParseSelector("team=finance")
+	r.AddRule(Rule{Selector: sel, Outcomes: []worker.Outcome{worker.Failure, worker.Timeout}, Notifiers: []Notifier{finance}})
+	r.AddRule(Rule{Events: []worker.EventType{worker.EventCircuitOpen}, Notifiers: []Notifier{ops}})
+
+	if err := r.AddRule(Rule{}); err == nil {
+		t.Error("Failed to detect rule without notifiers")
+	}
+
+	ctx := context.Background()
+	failed := Alert{Key: "report/failure", Job: "report", Event: worker.EventRunFinished, Outcome: worker.Failure, Labels: map[string]string{"team": "finance"}}
+
+	if err := r.Route(ctx, failed); err != nil || finance.count() != 1 {
+		t.Error("Failed to route alert: ", err, finance.count())
+	}
+
+	if err := r.Route(ctx, failed); !errors.Is(err, ErrDuplicate) || finance.count() != 1 {
+		t.Error("Failed to suppress duplicate alert: ", err)
+	}
+
+	succeeded := failed
+	succeeded.Key, succeeded.Outcome = "report/success", worker.Success
+	other := failed
+	other.Key, other.Labels = "other/failure", map[string]string{"team": "ops"}
+
+	if r.Route(ctx, succeeded); finance.count() != 1 {
+		t.Error("Alert of success is routed")
+	}
+
+	if r.Route(ctx, other); finance.count() != 1 || ops.count() != 0 {
+		t.Error("Alert of other team is routed")
+	}
+
+	open := Alert{Key: "report/open", Job: "report", Event: worker.EventCircuitOpen}
+	if err := r.Route(ctx, open); err == nil || ops.count() != 1 {
+		t.Error("Failed to route alert by event or to return error of notifier: ", err)
+	}
+
+	if _, err := NewRouter(-time.Second, 0, 0); err == nil {
+		t.Error("Failed to detect wrong de-duplication window")
+	}
+
+	if _, err := NewRouter(0, 5, 0); err == nil {
+		t.Error("Failed to detect wrong rate")
+	}
+}
+
+func Test_Router_RateLimit(t *testing.T) {
+	rec := &recorder{}
+	r, _ := NewRouter(0, 2, time.Millisecond*100)
+	r.AddRule(Rule{Notifiers: []Notifier{rec}})
+
+	ctx := context.Background()
+	limited := 0
+	for i := 0; i < 5; i++ {
+		if err := r.Route(ctx, Alert{Key: fmt.Sprint(i)}); errors.Is(err, ErrRateLimited) {
+			limited++
+		}
+	}
+
+	if rec.count() != 2 || limited != 3 {
+		t.Error("Failed to limit rate of alerts: ", rec.count(), limited)
+	}
+
+	time.Sleep(time.Millisecond * 60)
+
+	if err := r.Route(ctx, Alert{Key: "later"}); err != nil || rec.count() != 3 {
+		t.Error("Failed to refill rate limit: ", err)
+	}
+}
+
+func Test_Router_Handle(t *testing.T) {
+	alerts := make(chan string, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		alerts <- r.URL.Path
+	}))
+	defer srv.Close()
+
+	h, _ := NewWebhook(srv.URL+"/alerts", srv.Client())
+	r, _ := NewRouter(time.Minute, 0, 0)
+	sel, _ := worker.ParseSelector("team=finance")
+	r.AddRule(Rule{Selector: sel, Outcomes: []worker.Outcome{worker.Failure}, Notifiers: []Notifier{h}})
+
+	errs := make(chan error, 10)
+	r.SetErrorHandler(func(a Alert, err error) {
+		errs <- err
+	})
+
+	w := worker.NewWorker()
+	w.Subscribe(r.Handle)
+
+	a, _ := tk.Create(time.Millisecond*10, time.Second, 0, func(ctx context.Context) error {
+		return fmt.Errorf("Ledger is locked")
+	})
+	a.SetMaxRuns(3)
+
+	w.Add(a, "ledger")
+	w.SetLabels("ledger", map[string]string{"team": "finance"})
+	w.Start("ledger")
+
+	time.Sleep(time.Millisecond * 100)
+
+	if len(alerts) != 1 || <-alerts != "/alerts" || len(errs) != 0 {
+		t.Error("Wrong alerts of failed job: ", len(alerts), len(errs))
+	}
+}
@@ -0,0 +1,116 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// notifier sending alerts by email through SMTP server
+type SMTP struct {
+	addr string
+	host string
+	from string
+	to   []string
+	auth smtp.Auth
+}
+
+// creates notifier sending alerts from address from to addresses to through SMTP server at addr host:port,
+// auth is used if server supports it, connection is upgraded by STARTTLS if server supports it
+func NewSMTP(addr string, from string, to []string, auth smtp.Auth) (*SMTP, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("Address %q is not valid: %v", addr, err)
+	}
+
+	if from == "" || len(to) == 0 {
+		return nil, fmt.Errorf("No sender or recipients provided")
+	}
+
+	for _, a := range append([]string{from}, to...) {
+		if strings.ContainsAny(a, "\r\n") {
+			return nil, fmt.Errorf("Address %q is not valid", a)
+		}
+	}
+
+	return &SMTP{addr: addr, host: host, from: from, to: append([]string(nil), to...), auth: auth}, nil
+}
+
+// sends alert by email
+func (s *SMTP) Notify(ctx context.Context, a Alert) error {
+	d := net.Dialer{}
+	conn, err := d.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("Failed to connect to SMTP server: %v", err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("Failed to connect to SMTP server: %v", err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return fmt.Errorf("Failed to start TLS: %v", err)
+		}
+	}
+
+	if ok, _ := c.Extension("AUTH"); ok && s.auth != nil {
+		if err := c.Auth(s.auth); err != nil {
+			return fmt.Errorf("Failed to authenticate: %v", err)
+		}
+	}
+
+	if err := c.Mail(s.from); err != nil {
+		return fmt.Errorf("Failed to send alert: %v", err)
+	}
+
+	for _, to := range s.to {
+		if err := c.Rcpt(to); err != nil {
+			return fmt.Errorf("Failed to send alert to %v: %v", to, err)
+		}
+	}
+
+	wc, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("Failed to send alert: %v", err)
+	}
+
+	if _, err := wc.Write(s.message(a)); err != nil {
+		wc.Close()
+		return fmt.Errorf("Failed to send alert: %v", err)
+	}
+
+	if err := wc.Close(); err != nil {
+		return fmt.Errorf("Failed to send alert: %v", err)
+	}
+
+	return c.Quit()
+}
+
+// returns email of alert
+func (s *SMTP) message(a Alert) []byte {
+	subject := strings.NewReplacer("\r", " ", "\n", " ").Replace(a.Subject())
+	body := strings.ReplaceAll(strings.ReplaceAll(a.String(), "\r\n", "\n"), "\n", "\r\n")
+
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "From: %v\r\n", s.from)
+	fmt.Fprintf(b, "To: %v\r\n", strings.Join(s.to, ", "))
+	fmt.Fprintf(b, "Subject: %v\r\n", subject)
+	fmt.Fprintf(b, "Date: %v\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(b, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(body)
+
+	return b.Bytes()
+}
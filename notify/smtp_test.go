@@ -0,0 +1,111 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vslchnk/goscheduler/worker"
+)
+
+// serves one SMTP session on l, sends received message to mails
+func serveSMTP(t *testing.T, l net.Listener, mails chan<- string) {
+	conn, err := l.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(s string) {
+		conn.Write([]byte(s + "\r\n"))
+	}
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL"), strings.HasPrefix(cmd, "RCPT"):
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+
+			data := []string{}
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+
+				if l == ".\r\n" {
+					break
+				}
+				data = append(data, l)
+			}
+
+			mails <- strings.Join(data, "")
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			t.Error("Unexpected SMTP command: ", cmd)
+			reply("500 Unknown command")
+		}
+	}
+}
+
+func Test_SMTP_Notify(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("Failed to listen: ", err)
+	}
+	defer l.Close()
+
+	mails := make(chan string, 1)
+	go serveSMTP(t, l, mails)
+
+	s, err := NewSMTP(l.Addr().String(), "scheduler@example.com", []string{"ops@example.com", "dev@example.com"}, nil)
+	if err != nil {
+		t.Fatal("Failed to create notifier: ", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	a := Alert{Job: "report", Event: worker.EventRunFinished, Outcome: worker.Timeout, Message: "Task time has expired", Labels: map[string]string{"team": "finance"}}
+	if err := s.Notify(ctx, a); err != nil {
+		t.Fatal("Failed to send alert: ", err)
+	}
+
+	mail := <-mails
+	for _, want := range []string{"To: ops@example.com, dev@example.com\r\n", "Subject: Job report: run finished, timeout\r\n", "Labels: team=finance\r\n", "Message: Task time has expired\r\n"} {
+		if !strings.Contains(mail, want) {
+			t.Error("Mail doesn't contain ", want, ": ", mail)
+		}
+	}
+
+	if _, err := NewSMTP("localhost", "a@example.com", []string{"b@example.com"}, nil); err == nil {
+		t.Error("Failed to detect wrong address")
+	}
+
+	if _, err := NewSMTP("localhost:25", "a@example.com\r\nBcc: c@example.com", []string{"b@example.com"}, nil); err == nil {
+		t.Error("Failed to detect header injection")
+	}
+
+	l.Close()
+
+	if err := s.Notify(ctx, a); err == nil {
+		t.Error("Failed to detect closed server")
+	}
+}
@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// notifier posting alerts as JSON to URL
+type Webhook struct {
+	sync.Mutex
+	url     string
+	client  *http.Client
+	headers http.Header
+}
+
+// creates webhook posting alerts to url with client, http.DefaultClient is used if client is nil
+func NewWebhook(rawURL string, client *http.Client) (*Webhook, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("URL %q is not valid", rawURL)
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Webhook{url: rawURL, client: client, headers: make(http.Header)}, nil
+}
+
+// sets header of requests of webhook, e.g. authorization
+func (h *Webhook) SetHeader(key string, value string) {
+	defer h.Unlock()
+	h.Lock()
+
+	h.headers.Set(key, value)
+}
+
+// posts alert as JSON, response with status other than 2xx is error
+func (h *Webhook) Notify(ctx context.Context, a Alert) error {
+	body, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("Failed to encode alert: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("Failed to create request: %v", err)
+	}
+
+	h.Lock()
+	req.Header = h.headers.Clone()
+	h.Unlock()
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Failed to post alert: %v", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Failed to post alert: status %v", resp.Status)
+	}
+
+	return nil
+}
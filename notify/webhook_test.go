@@ -0,0 +1,57 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/vslchnk/goscheduler/worker"
+)
+
+func Test_Webhook_Notify(t *testing.T) {
+	alerts := make(chan Alert, 1)
+	status := http.StatusOK
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a := Alert{}
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" || r.Header.Get("Authorization") != "Bearer secret" {
+			t.Error("Wrong request: ", r.Method, r.Header)
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+			t.Error("Failed to decode alert: ", err)
+		}
+
+		alerts <- a
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	h, err := NewWebhook(srv.URL, srv.Client())
+	if err != nil {
+		t.Fatal("Failed to create webhook: ", err)
+	}
+	h.SetHeader("Authorization", "Bearer secret")
+
+	sent := Alert{Key: "k", Job: "report", Event: worker.EventRunFinished, Outcome: worker.Failure, Time: time.Now().Round(0), Message: "Boom", Labels: map[string]string{"team": "finance"}}
+	if err := h.Notify(context.Background(), sent); err != nil {
+		t.Fatal("Failed to post alert: ", err)
+	}
+
+	if a := <-alerts; a.Job != sent.Job || a.Outcome != sent.Outcome || !a.Time.Equal(sent.Time) || a.Labels["team"] != "finance" {
+		t.Error("Wrong alert: ", a)
+	}
+
+	status = http.StatusInternalServerError
+	if err := h.Notify(context.Background(), sent); err == nil {
+		t.Error("Failed to detect error status")
+	}
+	<-alerts
+
+	if _, err := NewWebhook("ftp://example.com", nil); err == nil {
+		t.Error("Failed to detect wrong URL")
+	}
+}
@@ -0,0 +1,166 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// message kept by memory queue
+type entry struct {
+	m       Message
+	seq     int
+	visible time.Time
+}
+
+// queue keeping messages in memory of process, shared by consumers of one process
+type Memory struct {
+	sync.Mutex
+	maxAttempts int
+	seq         int
+	entries     map[string]*entry
+	dead        []Message
+}
+
+// creates memory queue dead-lettering messages delivered maxAttempts times
+func NewMemory(maxAttempts int) (*Memory, error) {
+	if maxAttempts <= 0 {
+		return nil, fmt.Errorf("Max attempts must be greater than 0")
+	}
+
+	return &Memory{maxAttempts: maxAttempts, entries: make(map[string]*entry)}, nil
+}
+
+// puts message to queue
+func (q *Memory) Enqueue(ctx context.Context, m Message) error {
+	defer q.Unlock()
+	q.Lock()
+
+	if m.ID == "" {
+		m.ID = newID()
+	}
+
+	if _, ok := q.entries[m.ID]; ok {
+		return fmt.Errorf("Message with ID %v already exist", m.ID)
+	}
+
+	m.Payload = append([]byte(nil), m.Payload...)
+	m.Attempts = 0
+	m.Receipt = ""
+	q.seq++
+	q.entries[m.ID] = &entry{m: m, seq: q.seq}
+
+	return nil
+}
+
+// receives the oldest visible message
+func (q *Memory) Receive(ctx context.Context, visibility time.Duration) (Message, error) {
+	if visibility <= 0 {
+		return Message{}, fmt.Errorf("Visibility timeout must be greater than 0")
+	}
+
+	defer q.Unlock()
+	q.Lock()
+
+	now := time.Now()
+	visible := []*entry{}
+	for _, e := range q.entries {
+		if !e.visible.After(now) {
+			visible = append(visible, e)
+		}
+	}
+
+	sort.Slice(visible, func(i, k int) bool {
+		if !visible[i].visible.Equal(visible[k].visible) {
+			return visible[i].visible.Before(visible[k].visible)
+		}
+
+		return visible[i].seq < visible[k].seq
+	})
+
+	for _, e := range visible {
+		if e.m.Attempts >= q.maxAttempts {
+			delete(q.entries, e.m.ID)
+			q.dead = append(q.dead, e.m)
+			continue
+		}
+
+		e.m.Attempts++
+		e.m.Receipt = newID()
+		e.visible = now.Add(visibility)
+
+		return e.m, nil
+	}
+
+	return Message{}, ErrEmpty
+}
+
+// removes message from queue
+func (q *Memory) Ack(ctx context.Context, m Message) error {
+	defer q.Unlock()
+	q.Lock()
+
+	e, err := q.received(m)
+	if err != nil {
+		return err
+	}
+
+	delete(q.entries, e.m.ID)
+
+	return nil
+}
+
+// hides message for visibility from now on
+func (q *Memory) Extend(ctx context.Context, m Message, visibility time.Duration) error {
+	if visibility <= 0 {
+		return fmt.Errorf("Visibility timeout must be greater than 0")
+	}
+
+	defer q.Unlock()
+	q.Lock()
+
+	e, err := q.received(m)
+	if err != nil {
+		return err
+	}
+
+	e.visible = time.Now().Add(visibility)
+
+	return nil
+}
+
+// makes message visible after delay
+func (q *Memory) Nack(ctx context.Context, m Message, delay time.Duration) error {
+	defer q.Unlock()
+	q.Lock()
+
+	e, err := q.received(m)
+	if err != nil {
+		return err
+	}
+
+	e.m.Receipt = ""
+	e.visible = time.Now().Add(delay)
+
+	return nil
+}
+
+// returns dead-lettered messages
+func (q *Memory) DeadLetters(ctx context.Context) ([]Message, error) {
+	defer q.Unlock()
+	q.Lock()
+
+	return append([]Message(nil), q.dead...), nil
+}
+
+// returns entry of message received with its receipt, queue must be locked
+func (q *Memory) received(m Message) (*entry, error) {
+	e, ok := q.entries[m.ID]
+	if !ok || m.Receipt == "" || e.m.Receipt != m.Receipt {
+		return nil, ErrStaleReceipt
+	}
+
+	return e, nil
+}
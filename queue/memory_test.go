@@ -0,0 +1,18 @@
+package queue
+
+import (
+	"testing"
+)
+
+func Test_Memory_Queue(t *testing.T) {
+	q, err := NewMemory(2)
+	if err != nil {
+		t.Fatal("Failed to create queue: ", err)
+	}
+
+	testQueue(t, q)
+
+	if _, err := NewMemory(0); err == nil {
+		t.Error("Failed to detect wrong max attempts")
+	}
+}
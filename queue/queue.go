@@ -0,0 +1,52 @@
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// errors of queues
+var (
+	ErrEmpty        = errors.New("Queue has no visible messages")
+	ErrStaleReceipt = errors.New("Receipt of message is stale")
+)
+
+// request of job's run put to queue by scheduler and executed by consumer
+type Message struct {
+	ID       string
+	Job      string
+	RunID    string
+	Planned  time.Time
+	Payload  []byte
+	Attempts int
+	Receipt  string
+}
+
+// queue of run requests with at-least-once delivery: received message is hidden for visibility timeout
+// and delivered again unless it's acknowledged, message delivered max attempts times is dead-lettered
+type Queue interface {
+	// puts message to queue, sets its ID if it's empty
+	Enqueue(ctx context.Context, m Message) error
+	// receives next visible message and hides it for visibility, returns ErrEmpty if there is none
+	Receive(ctx context.Context, visibility time.Duration) (Message, error)
+	// removes received message from queue, returns ErrStaleReceipt if message has been delivered again
+	Ack(ctx context.Context, m Message) error
+	// hides received message for visibility from now on, so it isn't delivered again while it's being handled;
+	// returns ErrStaleReceipt if message has been delivered again
+	Extend(ctx context.Context, m Message, visibility time.Duration) error
+	// makes received message visible again after delay, returns ErrStaleReceipt if message has been delivered again
+	Nack(ctx context.Context, m Message, delay time.Duration) error
+	// returns dead-lettered messages
+	DeadLetters(ctx context.Context) ([]Message, error)
+}
+
+// returns new random ID of message or receipt
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
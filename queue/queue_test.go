@@ -0,0 +1,101 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// checks delivery, acknowledgement, redelivery and dead-lettering of queue dead-lettering after 2 attempts
+func testQueue(t *testing.T, q Queue) {
+	ctx := context.Background()
+
+	if _, err := q.Receive(ctx, time.Second); !errors.Is(err, ErrEmpty) {
+		t.Error("Failed to detect empty queue: ", err)
+	}
+
+	planned := time.Now().Truncate(time.Second)
+	for _, id := range []string{"a", "b"} {
+		if err := q.Enqueue(ctx, Message{ID: id, Job: "job", RunID: "job-" + id, Planned: planned, Payload: []byte(`{"n":1}`)}); err != nil {
+			t.Fatal("Failed to enqueue message: ", err)
+		}
+	}
+
+	a, err := q.Receive(ctx, time.Millisecond*30)
+	if err != nil || a.ID != "a" || a.Job != "job" || a.RunID != "job-a" || !a.Planned.Equal(planned) || string(a.Payload) != `{"n":1}` || a.Attempts != 1 {
+		t.Fatal("Failed to receive the oldest message: ", a, err)
+	}
+
+	b, err := q.Receive(ctx, time.Second)
+	if err != nil || b.ID != "b" {
+		t.Fatal("Failed to receive next message: ", b, err)
+	}
+
+	if _, err := q.Receive(ctx, time.Second); !errors.Is(err, ErrEmpty) {
+		t.Error("Failed to hide received messages: ", err)
+	}
+
+	if err := q.Ack(ctx, b); err != nil {
+		t.Error("Failed to acknowledge message: ", err)
+	}
+
+	if err := q.Ack(ctx, b); !errors.Is(err, ErrStaleReceipt) {
+		t.Error("Failed to detect acknowledged message: ", err)
+	}
+
+	time.Sleep(time.Millisecond * 40)
+
+	again, err := q.Receive(ctx, time.Second)
+	if err != nil || again.ID != "a" || again.Attempts != 2 {
+		t.Fatal("Failed to deliver message again after visibility timeout: ", again, err)
+	}
+
+	if err := q.Ack(ctx, a); !errors.Is(err, ErrStaleReceipt) {
+		t.Error("Failed to detect stale receipt: ", err)
+	}
+
+	if err := q.Nack(ctx, again, 0); err != nil {
+		t.Error("Failed to return message to queue: ", err)
+	}
+
+	if _, err := q.Receive(ctx, time.Second); !errors.Is(err, ErrEmpty) {
+		t.Error("Failed to dead-letter message: ", err)
+	}
+
+	dead, err := q.DeadLetters(ctx)
+	if err != nil || len(dead) != 1 || dead[0].ID != "a" || dead[0].Attempts != 2 {
+		t.Error("Wrong dead letters: ", dead, err)
+	}
+
+	if err := q.Enqueue(ctx, Message{ID: "c", Job: "job", RunID: "job-c", Planned: planned}); err != nil {
+		t.Fatal("Failed to enqueue message: ", err)
+	}
+
+	c, err := q.Receive(ctx, time.Millisecond*30)
+	if err != nil || c.ID != "c" {
+		t.Fatal("Failed to receive message: ", c, err)
+	}
+
+	if err := q.Extend(ctx, c, 0); err == nil {
+		t.Error("Failed to detect wrong visibility timeout")
+	}
+
+	if err := q.Extend(ctx, c, time.Second); err != nil {
+		t.Error("Failed to extend visibility timeout: ", err)
+	}
+
+	time.Sleep(time.Millisecond * 40)
+
+	if _, err := q.Receive(ctx, time.Second); !errors.Is(err, ErrEmpty) {
+		t.Error("Failed to hide message with extended visibility timeout: ", err)
+	}
+
+	if err := q.Extend(ctx, Message{ID: "c", Receipt: "stale"}, time.Second); !errors.Is(err, ErrStaleReceipt) {
+		t.Error("Failed to detect stale receipt: ", err)
+	}
+
+	if err := q.Ack(ctx, c); err != nil {
+		t.Error("Failed to acknowledge message: ", err)
+	}
+}
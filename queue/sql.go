@@ -0,0 +1,194 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// queue keeping messages in table of SQL database, shared by consumers of many processes;
+// queries use ? placeholders as SQLite and MySQL do, visibility of messages is compared by clocks of processes
+type SQL struct {
+	db          *sql.DB
+	table       string
+	maxAttempts int
+}
+
+// creates SQL queue keeping messages in table and dead-lettering messages delivered maxAttempts times
+func NewSQL(db *sql.DB, table string, maxAttempts int) (*SQL, error) {
+	if db == nil {
+		return nil, fmt.Errorf("No database provided")
+	}
+
+	if !identifier.MatchString(table) {
+		return nil, fmt.Errorf("Table name %q is not valid", table)
+	}
+
+	if maxAttempts <= 0 {
+		return nil, fmt.Errorf("Max attempts must be greater than 0")
+	}
+
+	return &SQL{db: db, table: table, maxAttempts: maxAttempts}, nil
+}
+
+// creates table of messages if it doesn't exist
+func (q *SQL) CreateTable(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %v (id VARCHAR(64) PRIMARY KEY, job VARCHAR(255) NOT NULL, run_id VARCHAR(255) NOT NULL, planned BIGINT NOT NULL, payload BLOB, attempts INTEGER NOT NULL, receipt VARCHAR(64) NOT NULL, visible BIGINT NOT NULL, enqueued BIGINT NOT NULL, dead INTEGER NOT NULL)", q.table))
+	if err != nil {
+		return fmt.Errorf("Failed to create table of messages: %v", err)
+	}
+
+	return nil
+}
+
+// puts message to queue
+func (q *SQL) Enqueue(ctx context.Context, m Message) error {
+	if m.ID == "" {
+		m.ID = newID()
+	}
+
+	now := time.Now().UnixNano()
+	_, err := q.db.ExecContext(ctx, fmt.Sprintf("INSERT INTO %v (id, job, run_id, planned, payload, attempts, receipt, visible, enqueued, dead) VALUES (?, ?, ?, ?, ?, 0, '', ?, ?, 0)", q.table), m.ID, m.Job, m.RunID, m.Planned.UnixNano(), m.Payload, now, now)
+	if err != nil {
+		return fmt.Errorf("Failed to enqueue message: %v", err)
+	}
+
+	return nil
+}
+
+// receives the oldest visible message, message is taken by updating its receipt, so only one consumer gets it
+func (q *SQL) Receive(ctx context.Context, visibility time.Duration) (Message, error) {
+	if visibility <= 0 {
+		return Message{}, fmt.Errorf("Visibility timeout must be greater than 0")
+	}
+
+	for {
+		now := time.Now().UnixNano()
+
+		var id, receipt string
+		var attempts int
+		err := q.db.QueryRowContext(ctx, fmt.Sprintf("SELECT id, attempts, receipt FROM %v WHERE dead = 0 AND visible <= ? ORDER BY visible, enqueued LIMIT 1", q.table), now).Scan(&id, &attempts, &receipt)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return Message{}, ErrEmpty
+		}
+
+		if err != nil {
+			return Message{}, fmt.Errorf("Failed to receive message: %v", err)
+		}
+
+		if attempts >= q.maxAttempts {
+			if _, err := q.db.ExecContext(ctx, fmt.Sprintf("UPDATE %v SET dead = 1 WHERE id = ? AND receipt = ?", q.table), id, receipt); err != nil {
+				return Message{}, fmt.Errorf("Failed to dead-letter message: %v", err)
+			}
+
+			continue
+		}
+
+		next := newID()
+		res, err := q.db.ExecContext(ctx, fmt.Sprintf("UPDATE %v SET attempts = attempts + 1, receipt = ?, visible = ? WHERE id = ? AND receipt = ? AND visible <= ?", q.table), next, now+int64(visibility), id, receipt, now)
+		if err != nil {
+			return Message{}, fmt.Errorf("Failed to receive message: %v", err)
+		}
+
+		if n, err := res.RowsAffected(); err != nil {
+			return Message{}, fmt.Errorf("Failed to receive message: %v", err)
+		} else if n == 0 {
+			continue
+		}
+
+		m := Message{ID: id, Receipt: next}
+		var planned int64
+		err = q.db.QueryRowContext(ctx, fmt.Sprintf("SELECT job, run_id, planned, payload, attempts FROM %v WHERE id = ?", q.table), id).Scan(&m.Job, &m.RunID, &planned, &m.Payload, &m.Attempts)
+		if err != nil {
+			return Message{}, fmt.Errorf("Failed to receive message: %v", err)
+		}
+
+		m.Planned = time.Unix(0, planned)
+
+		return m, nil
+	}
+}
+
+// removes message from queue
+func (q *SQL) Ack(ctx context.Context, m Message) error {
+	if m.Receipt == "" {
+		return ErrStaleReceipt
+	}
+
+	res, err := q.db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %v WHERE id = ? AND receipt = ? AND dead = 0", q.table), m.ID, m.Receipt)
+
+	return received(res, err)
+}
+
+// hides message for visibility from now on
+func (q *SQL) Extend(ctx context.Context, m Message, visibility time.Duration) error {
+	if visibility <= 0 {
+		return fmt.Errorf("Visibility timeout must be greater than 0")
+	}
+
+	if m.Receipt == "" {
+		return ErrStaleReceipt
+	}
+
+	res, err := q.db.ExecContext(ctx, fmt.Sprintf("UPDATE %v SET visible = ? WHERE id = ? AND receipt = ? AND dead = 0", q.table), time.Now().Add(visibility).UnixNano(), m.ID, m.Receipt)
+
+	return received(res, err)
+}
+
+// makes message visible after delay
+func (q *SQL) Nack(ctx context.Context, m Message, delay time.Duration) error {
+	if m.Receipt == "" {
+		return ErrStaleReceipt
+	}
+
+	res, err := q.db.ExecContext(ctx, fmt.Sprintf("UPDATE %v SET receipt = '', visible = ? WHERE id = ? AND receipt = ? AND dead = 0", q.table), time.Now().Add(delay).UnixNano(), m.ID, m.Receipt)
+
+	return received(res, err)
+}
+
+// returns dead-lettered messages
+func (q *SQL) DeadLetters(ctx context.Context) ([]Message, error) {
+	rows, err := q.db.QueryContext(ctx, fmt.Sprintf("SELECT id, job, run_id, planned, payload, attempts FROM %v WHERE dead = 1 ORDER BY enqueued", q.table))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read dead letters: %v", err)
+	}
+	defer rows.Close()
+
+	msgs := []Message{}
+	for rows.Next() {
+		m := Message{}
+		var planned int64
+		if err := rows.Scan(&m.ID, &m.Job, &m.RunID, &planned, &m.Payload, &m.Attempts); err != nil {
+			return nil, fmt.Errorf("Failed to read dead letters: %v", err)
+		}
+
+		m.Planned = time.Unix(0, planned)
+		msgs = append(msgs, m)
+	}
+
+	return msgs, rows.Err()
+}
+
+// returns error of query or ErrStaleReceipt if query hasn't changed any row
+func received(res sql.Result, err error) error {
+	if err != nil {
+		return fmt.Errorf("Failed to update message: %v", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Failed to update message: %v", err)
+	}
+
+	if n == 0 {
+		return ErrStaleReceipt
+	}
+
+	return nil
+}
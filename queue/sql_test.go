@@ -0,0 +1,256 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// row of table of messages
+type messageRow struct {
+	id       string
+	job      string
+	runID    string
+	planned  int64
+	payload  []byte
+	attempts int64
+	receipt  string
+	visible  int64
+	enqueued int64
+	seq      int
+	dead     bool
+}
+
+// driver of database understanding only queries of SQL queue, keeps table in memory
+type messageDriver struct {
+	sync.Mutex
+	seq  int
+	rows map[string]*messageRow
+}
+
+var messages = &messageDriver{rows: make(map[string]*messageRow)}
+
+func init() {
+	sql.Register("messages", messages)
+}
+
+func (d *messageDriver) Open(name string) (driver.Conn, error) {
+	return messageConn{d}, nil
+}
+
+type messageConn struct {
+	d *messageDriver
+}
+
+func (c messageConn) Prepare(query string) (driver.Stmt, error) {
+	return messageStmt{d: c.d, query: query}, nil
+}
+
+func (c messageConn) Close() error {
+	return nil
+}
+
+func (c messageConn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("Transactions are not supported")
+}
+
+type messageStmt struct {
+	d     *messageDriver
+	query string
+}
+
+func (s messageStmt) Close() error {
+	return nil
+}
+
+func (s messageStmt) NumInput() int {
+	return -1
+}
+
+// returns number of changed rows as result
+func affected(ok bool) driver.Result {
+	if ok {
+		return driver.RowsAffected(1)
+	}
+
+	return driver.RowsAffected(0)
+}
+
+func (s messageStmt) Exec(args []driver.Value) (driver.Result, error) {
+	defer s.d.Unlock()
+	s.d.Lock()
+
+	q := s.query
+	switch {
+	case strings.HasPrefix(q, "CREATE TABLE"):
+		return driver.RowsAffected(0), nil
+	case strings.HasPrefix(q, "INSERT"):
+		id := args[0].(string)
+		if _, ok := s.d.rows[id]; ok {
+			return nil, fmt.Errorf("UNIQUE constraint failed")
+		}
+
+		payload, _ := args[4].([]byte)
+		s.d.seq++
+		s.d.rows[id] = &messageRow{id: id, job: args[1].(string), runID: args[2].(string), planned: args[3].(int64), payload: payload, visible: args[5].(int64), enqueued: args[6].(int64), seq: s.d.seq}
+
+		return driver.RowsAffected(1), nil
+	case strings.Contains(q, "SET dead = 1"):
+		r, ok := s.d.rows[args[0].(string)]
+		ok = ok && r.receipt == args[1].(string)
+		if ok {
+			r.dead = true
+		}
+
+		return affected(ok), nil
+	case strings.Contains(q, "SET attempts = attempts + 1"):
+		r, ok := s.d.rows[args[2].(string)]
+		ok = ok && r.receipt == args[3].(string) && r.visible <= args[4].(int64)
+		if ok {
+			r.attempts++
+			r.receipt = args[0].(string)
+			r.visible = args[1].(int64)
+		}
+
+		return affected(ok), nil
+	case strings.HasPrefix(q, "DELETE"):
+		r, ok := s.d.rows[args[0].(string)]
+		ok = ok && r.receipt == args[1].(string) && !r.dead
+		if ok {
+			delete(s.d.rows, r.id)
+		}
+
+		return affected(ok), nil
+	case strings.Contains(q, "SET visible = ?"):
+		r, ok := s.d.rows[args[1].(string)]
+		ok = ok && r.receipt == args[2].(string) && !r.dead
+		if ok {
+			r.visible = args[0].(int64)
+		}
+
+		return affected(ok), nil
+	case strings.Contains(q, "SET receipt = ''"):
+		r, ok := s.d.rows[args[1].(string)]
+		ok = ok && r.receipt == args[2].(string) && !r.dead
+		if ok {
+			r.receipt = ""
+			r.visible = args[0].(int64)
+		}
+
+		return affected(ok), nil
+	}
+
+	return nil, fmt.Errorf("Query %q is not supported", q)
+}
+
+func (s messageStmt) Query(args []driver.Value) (driver.Rows, error) {
+	defer s.d.Unlock()
+	s.d.Lock()
+
+	rows := []*messageRow{}
+	for _, r := range s.d.rows {
+		rows = append(rows, r)
+	}
+	sort.Slice(rows, func(i, k int) bool {
+		return rows[i].seq < rows[k].seq
+	})
+
+	q := s.query
+	switch {
+	case strings.HasPrefix(q, "SELECT id, attempts, receipt"):
+		sort.SliceStable(rows, func(i, k int) bool {
+			return rows[i].visible < rows[k].visible
+		})
+
+		for _, r := range rows {
+			if !r.dead && r.visible <= args[0].(int64) {
+				return &messageRows{values: [][]driver.Value{{r.id, r.attempts, r.receipt}}}, nil
+			}
+		}
+
+		return &messageRows{}, nil
+	case strings.HasPrefix(q, "SELECT job"):
+		r, ok := s.d.rows[args[0].(string)]
+		if !ok {
+			return &messageRows{}, nil
+		}
+
+		return &messageRows{values: [][]driver.Value{{r.job, r.runID, r.planned, r.payload, r.attempts}}}, nil
+	case strings.Contains(q, "WHERE dead = 1"):
+		res := &messageRows{}
+		for _, r := range rows {
+			if r.dead {
+				res.values = append(res.values, []driver.Value{r.id, r.job, r.runID, r.planned, r.payload, r.attempts})
+			}
+		}
+
+		return res, nil
+	}
+
+	return nil, fmt.Errorf("Query %q is not supported", q)
+}
+
+type messageRows struct {
+	values [][]driver.Value
+}
+
+func (r *messageRows) Columns() []string {
+	if len(r.values) == 0 {
+		return []string{"id"}
+	}
+
+	cols := make([]string, len(r.values[0]))
+	for i := range cols {
+		cols[i] = fmt.Sprintf("c%d", i)
+	}
+
+	return cols
+}
+
+func (r *messageRows) Close() error {
+	return nil
+}
+
+func (r *messageRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+
+	return nil
+}
+
+func Test_SQL_Queue(t *testing.T) {
+	messages.Lock()
+	messages.rows = make(map[string]*messageRow)
+	messages.Unlock()
+
+	db, err := sql.Open("messages", "")
+	if err != nil {
+		t.Fatal("Failed to open database: ", err)
+	}
+	defer db.Close()
+
+	q, err := NewSQL(db, "messages", 2)
+	if err != nil {
+		t.Fatal("Failed to create queue: ", err)
+	}
+
+	if err := q.CreateTable(context.Background()); err != nil {
+		t.Fatal("Failed to create table: ", err)
+	}
+
+	testQueue(t, q)
+
+	if _, err := NewSQL(db, "messages; DROP", 2); err == nil {
+		t.Error("Failed to detect wrong table name")
+	}
+}
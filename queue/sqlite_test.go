@@ -0,0 +1,101 @@
+//go:build sqlite
+
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// opens SQLite database in temporary directory of test, tests of this file are run with -tags sqlite and need modernc.org/sqlite driver
+func openSQLite(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "messages.db")+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		t.Fatal("Failed to open database: ", err)
+	}
+
+	return db
+}
+
+func Test_SQLite_Queue(t *testing.T) {
+	db := openSQLite(t)
+	defer db.Close()
+
+	q, err := NewSQL(db, "messages", 2)
+	if err != nil {
+		t.Fatal("Failed to create queue: ", err)
+	}
+
+	if err := q.CreateTable(context.Background()); err != nil {
+		t.Fatal("Failed to create table: ", err)
+	}
+
+	testQueue(t, q)
+}
+
+func Test_SQLite_Consumers(t *testing.T) {
+	db := openSQLite(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	q, _ := NewSQL(db, "messages", 2)
+
+	if err := q.CreateTable(ctx); err != nil {
+		t.Fatal("Failed to create table: ", err)
+	}
+
+	for i := 0; i < 20; i++ {
+		if err := q.Enqueue(ctx, Message{Job: "job", RunID: "job"}); err != nil {
+			t.Fatal("Failed to enqueue message: ", err)
+		}
+	}
+
+	mu := sync.Mutex{}
+	got := map[string]int{}
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				m, err := q.Receive(ctx, time.Minute)
+				if err == ErrEmpty {
+					return
+				}
+
+				if err != nil {
+					t.Error("Failed to receive message: ", err)
+					return
+				}
+
+				mu.Lock()
+				got[m.ID]++
+				mu.Unlock()
+
+				if err := q.Ack(ctx, m); err != nil {
+					t.Error("Failed to acknowledge message: ", err)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if len(got) != 20 {
+		t.Error("Wrong number of received messages: ", len(got))
+	}
+
+	for id, n := range got {
+		if n != 1 {
+			t.Error("Message is received by several consumers: ", id, n)
+		}
+	}
+}
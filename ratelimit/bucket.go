@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// token bucket holding up to burst tokens and refilled with one token every interval, safe for concurrent use
+type Bucket struct {
+	sync.Mutex
+	every  time.Duration
+	burst  int
+	tokens float64
+	filled time.Time
+}
+
+// creates full token bucket refilled with one token every interval and holding up to burst tokens
+func NewBucket(every time.Duration, burst int) (*Bucket, error) {
+	if every <= 0 {
+		return nil, fmt.Errorf("Interval must be greater than 0")
+	}
+
+	if burst <= 0 {
+		return nil, fmt.Errorf("Burst must be greater than 0")
+	}
+
+	return &Bucket{every: every, burst: burst, tokens: float64(burst)}, nil
+}
+
+// returns interval of refilling and burst of bucket
+func (b *Bucket) Limit() (time.Duration, int) {
+	return b.every, b.burst
+}
+
+// adds tokens refilled till now, bucket must be locked
+func (b *Bucket) refill(now time.Time) {
+	if !b.filled.IsZero() && now.After(b.filled) {
+		b.tokens += float64(now.Sub(b.filled)) / float64(b.every)
+		if b.tokens > float64(b.burst) {
+			b.tokens = float64(b.burst)
+		}
+	}
+
+	if now.After(b.filled) {
+		b.filled = now
+	}
+}
+
+// takes token at now if there is one, returns false if bucket is empty
+func (b *Bucket) Allow(now time.Time) bool {
+	defer b.Unlock()
+	b.Lock()
+
+	b.refill(now)
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+
+	return true
+}
+
+// takes token at now even if bucket is empty, returns time to wait until token is refilled
+func (b *Bucket) Reserve(now time.Time) time.Duration {
+	defer b.Unlock()
+	b.Lock()
+
+	b.refill(now)
+	b.tokens--
+
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens * float64(b.every))
+}
+
+// returns token taken but not used to bucket
+func (b *Bucket) Return() {
+	defer b.Unlock()
+	b.Lock()
+
+	if b.tokens++; b.tokens > float64(b.burst) {
+		b.tokens = float64(b.burst)
+	}
+}
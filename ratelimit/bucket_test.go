@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_Bucket_Allow(t *testing.T) {
+	b, err := NewBucket(time.Second, 2)
+	if err != nil {
+		t.Fatal("Failed to create bucket: ", err)
+	}
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if !b.Allow(now) || !b.Allow(now) || b.Allow(now) {
+		t.Error("Failed to limit burst")
+	}
+
+	if b.Allow(now.Add(time.Millisecond*900)) || !b.Allow(now.Add(time.Second)) {
+		t.Error("Failed to refill token")
+	}
+
+	b.Return()
+	if !b.Allow(now.Add(time.Second)) || b.Allow(now.Add(time.Second)) {
+		t.Error("Failed to return token")
+	}
+
+	// bucket doesn't hold more than burst tokens
+	later := now.Add(time.Hour)
+	if !b.Allow(later) || !b.Allow(later) || b.Allow(later) {
+		t.Error("Bucket holds more than burst tokens")
+	}
+
+	if _, err := NewBucket(0, 1); err == nil {
+		t.Error("Failed to detect wrong interval")
+	}
+
+	if _, err := NewBucket(time.Second, 0); err == nil {
+		t.Error("Failed to detect wrong burst")
+	}
+}
+
+func Test_Bucket_Reserve(t *testing.T) {
+	b, _ := NewBucket(time.Second, 1)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	waits := []time.Duration{}
+	for i := 0; i < 3; i++ {
+		waits = append(waits, b.Reserve(now))
+	}
+
+	if waits[0] != 0 || waits[1] != time.Second || waits[2] != time.Second*2 {
+		t.Error("Wrong waits of reservations: ", waits)
+	}
+
+	if b.Allow(now.Add(time.Second * 2)) {
+		t.Error("Reserved token is taken again")
+	}
+
+	b, _ = NewBucket(time.Millisecond, 10)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.Reserve(now)
+		}()
+	}
+	wg.Wait()
+
+	if w := b.Reserve(now); w != time.Millisecond {
+		t.Error("Wrong wait after concurrent reservations: ", w)
+	}
+}
@@ -0,0 +1,34 @@
+package task
+
+import (
+	"fmt"
+	"time"
+)
+
+// sets circuit breaker of task: job is suspended after threshold consecutive failed runs and tries one run again after cool-down,
+// zero cool-down keeps job suspended until its breaker is reset
+func (t *Task) SetCircuitBreaker(threshold int, coolDown time.Duration) error {
+	if threshold <= 0 {
+		return fmt.Errorf("Failure threshold must be greater than 0")
+	}
+
+	if err := checkDuration("Cool-down", coolDown); err != nil {
+		return err
+	}
+
+	t.failureThreshold = threshold
+	t.coolDown = coolDown
+
+	return nil
+}
+
+// removes circuit breaker of task
+func (t *Task) ClearCircuitBreaker() {
+	t.failureThreshold = 0
+	t.coolDown = 0
+}
+
+// returns failure threshold and cool-down of task's circuit breaker, zero threshold if task has no breaker
+func (t *Task) GetCircuitBreaker() (int, time.Duration) {
+	return t.failureThreshold, t.coolDown
+}
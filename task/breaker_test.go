@@ -0,0 +1,39 @@
+package task
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_Breaker_SetGet(t *testing.T) {
+	task, err := Create(time.Minute, time.Second, 0, outer("hello"))
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	if threshold, coolDown := task.GetCircuitBreaker(); threshold != 0 || coolDown != 0 {
+		t.Error("Wrong default circuit breaker: ", threshold, coolDown)
+	}
+
+	if err := task.SetCircuitBreaker(0, time.Second); err == nil {
+		t.Error("Failed to detect wrong failure threshold")
+	}
+
+	if err := task.SetCircuitBreaker(3, -time.Second); !errors.Is(err, ErrInvalidDuration) {
+		t.Error("Failed to detect negative cool-down: ", err)
+	}
+
+	task.SetCircuitBreaker(3, time.Minute)
+
+	if threshold, coolDown := task.GetCircuitBreaker(); threshold != 3 || coolDown != time.Minute {
+		t.Error("Failed to set circuit breaker: ", threshold, coolDown)
+	}
+
+	task.ClearCircuitBreaker()
+
+	if threshold, _ := task.GetCircuitBreaker(); threshold != 0 {
+		t.Error("Failed to clear circuit breaker")
+	}
+}
@@ -0,0 +1,68 @@
+package task
+
+import (
+	"fmt"
+	"time"
+)
+
+// max number of excluded dates skipped while looking for run of task with calendar
+const calendarSearchLimit = 1000
+
+// calendar of dates when task doesn't run, e.g. weekends and public holidays
+type Calendar interface {
+	// checks if date of tm in its location is excluded
+	Excludes(tm time.Time) bool
+}
+
+// sets calendar of task, runs planned on excluded dates in location of task are moved to the first run on included date
+func (t *Task) SetCalendar(c Calendar) error {
+	if c == nil {
+		return fmt.Errorf("No calendar provided")
+	}
+
+	t.calendar = c
+
+	return nil
+}
+
+// removes calendar of task
+func (t *Task) ClearCalendar() {
+	t.calendar = nil
+}
+
+// returns calendar of task, nil if task has none
+func (t *Task) GetCalendar() Calendar {
+	return t.calendar
+}
+
+// returns the earliest run from run planned at tm on which doesn't fall on date excluded by task's calendar,
+// zero time if there is none
+func (t Task) included(tm time.Time) time.Time {
+	if t.calendar == nil {
+		return tm
+	}
+
+	loc := t.GetLocation()
+
+	for i := 0; i < calendarSearchLimit && !tm.IsZero(); i++ {
+		if !t.calendar.Excludes(tm.In(loc)) {
+			return tm
+		}
+
+		y, m, d := tm.In(loc).Date()
+		midnight := time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+
+		switch {
+		case t.schedule != nil:
+			tm = t.schedule.next(midnight, true, loc)
+		case t.period > 0:
+			// keeps runs on the grid of periods
+			n := (midnight.Sub(tm) + t.period - 1) / t.period
+			tm = t.alignUp(tm.Add(n * t.period))
+		default:
+			tm = t.alignUp(midnight)
+		}
+	}
+
+	return time.Time{}
+}
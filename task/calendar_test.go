@@ -0,0 +1,59 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vslchnk/goscheduler/calendar"
+)
+
+func Test_Calendar_Next(t *testing.T) {
+	loc := location(t, "Europe/London")
+	holidays := calendar.New()
+	holidays.ExcludeDate(2024, time.December, 25, "Christmas")
+	holidays.ExcludeDate(2024, time.December, 26, "Boxing Day")
+
+	task := daily(t, TimeOfDay{Hour: 18}, loc)
+
+	if err := task.SetCalendar(nil); err == nil {
+		t.Error("Failed to detect missing calendar")
+	}
+
+	if err := task.SetCalendar(calendar.BusinessDays(holidays)); err != nil {
+		t.Fatal("Failed to set calendar: ", err)
+	}
+
+	runs := task.NextRuns(time.Date(2024, 12, 20, 19, 0, 0, 0, loc), 4)
+	want := []time.Time{
+		time.Date(2024, 12, 23, 18, 0, 0, 0, loc),
+		time.Date(2024, 12, 24, 18, 0, 0, 0, loc),
+		time.Date(2024, 12, 27, 18, 0, 0, 0, loc),
+		time.Date(2024, 12, 30, 18, 0, 0, 0, loc),
+	}
+
+	if len(runs) != len(want) {
+		t.Fatal("Wrong runs of business days: ", runs)
+	}
+
+	for i := range want {
+		if !runs[i].Equal(want[i]) {
+			t.Error("Wrong run of business days: ", i, runs[i])
+		}
+	}
+
+	// periodic runs stay on the grid of period
+	task.ClearSchedule()
+	task.SetPeriod(time.Hour * 5)
+
+	runs = task.NextRuns(time.Date(2024, 12, 25, 10, 0, 0, 0, loc), 2)
+	if len(runs) != 2 || !runs[0].Equal(time.Date(2024, 12, 27, 2, 0, 0, 0, loc)) || !runs[1].Equal(time.Date(2024, 12, 27, 7, 0, 0, 0, loc)) {
+		t.Error("Wrong periodic runs of business days: ", runs)
+	}
+
+	task.ClearCalendar()
+	task.SetMaxRuns(3)
+
+	if runs := task.NextRuns(time.Date(2024, 12, 24, 20, 0, 0, 0, loc), 10); len(runs) != 3 || !runs[0].Equal(time.Date(2024, 12, 24, 20, 0, 0, 0, loc)) || task.GetCalendar() != nil {
+		t.Error("Wrong runs of task without calendar: ", runs)
+	}
+}
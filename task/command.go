@@ -0,0 +1,167 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// default limit of captured stdout and stderr of command
+const DefaultOutputLimit = 64 * 1024
+
+// time given to command to exit after stop signal before its process group is killed
+const commandStopTimeout = 5 * time.Second
+
+// time command is waited for after its context is cancelled, worker waits as long for output of cancelled command
+const CommandWaitDelay = time.Second
+
+type stopKey struct{}
+
+// external command run by task
+type Command struct {
+	Path        string
+	Args        []string
+	Env         []string
+	Dir         string
+	OutputLimit int
+}
+
+// result of command's run, kept in run history
+type CommandResult struct {
+	ExitCode  int
+	Stdout    string
+	Stderr    string
+	Truncated bool
+}
+
+// error of command exited with non-zero code
+type ExitError struct {
+	Code   int
+	Stderr string
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("Command exited with code %d: %s", e.Code, e.Stderr)
+}
+
+// keeps first limit bytes written to it
+type limitedBuffer struct {
+	data      []byte
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if left := b.limit - len(b.data); left > 0 {
+		if len(p) > left {
+			b.data = append(b.data, p[:left]...)
+			b.truncated = true
+		} else {
+			b.data = append(b.data, p...)
+		}
+	} else if len(p) > 0 {
+		b.truncated = true
+	}
+
+	return len(p), nil
+}
+
+// creates task which runs external command, environment of command is extended by Env;
+// stopping job sends SIGTERM to command's process group, killing job or expired task time sends SIGKILL
+func CreateCommand(period time.Duration, taskTime time.Duration, delay time.Duration, cmd Command) (task Task, err error) {
+	if cmd.Path == "" {
+		return task, fmt.Errorf("No command provided")
+	}
+
+	if cmd.OutputLimit < 0 {
+		return task, fmt.Errorf("Output limit is less than 0")
+	}
+
+	if cmd.OutputLimit == 0 {
+		cmd.OutputLimit = DefaultOutputLimit
+	}
+
+	cmd.Args = append([]string(nil), cmd.Args...)
+	cmd.Env = append([]string(nil), cmd.Env...)
+
+	task, err = CreateWithResult(period, taskTime, delay, func(ctx context.Context) (interface{}, error) {
+		return cmd.run(ctx)
+	})
+	if err != nil {
+		return task, err
+	}
+
+	task.waitDelay = CommandWaitDelay
+
+	return task, nil
+}
+
+// returns time result of task's run is waited for after run is cancelled, zero if task's function isn't command
+func (t *Task) GetWaitDelay() time.Duration {
+	return t.waitDelay
+}
+
+// runs command under ctx and returns its result
+func (cmd Command) run(ctx context.Context) (CommandResult, error) {
+	c := exec.CommandContext(ctx, cmd.Path, cmd.Args...)
+	c.Dir = cmd.Dir
+	c.Env = append(os.Environ(), cmd.Env...)
+	c.WaitDelay = CommandWaitDelay
+
+	stdout := &limitedBuffer{limit: cmd.OutputLimit}
+	stderr := &limitedBuffer{limit: cmd.OutputLimit}
+	c.Stdout = stdout
+	c.Stderr = stderr
+
+	group(c)
+
+	res := CommandResult{ExitCode: -1}
+
+	if err := c.Start(); err != nil {
+		return res, fmt.Errorf("Failed to start command: %v", err)
+	}
+
+	finished := make(chan struct{})
+	defer close(finished)
+
+	go func() {
+		select {
+		case <-StopSignal(ctx):
+			terminate(c, finished)
+		case <-finished:
+		}
+	}()
+
+	err := c.Wait()
+
+	res.ExitCode = c.ProcessState.ExitCode()
+	res.Stdout = string(stdout.data)
+	res.Stderr = string(stderr.data)
+	res.Truncated = stdout.truncated || stderr.truncated
+
+	if ctx.Err() != nil {
+		return res, fmt.Errorf("Command is cancelled: %v", ctx.Err())
+	}
+
+	var ee *exec.ExitError
+	if errors.As(err, &ee) {
+		return res, &ExitError{Code: res.ExitCode, Stderr: res.Stderr}
+	}
+
+	return res, err
+}
+
+// returns context of run with channel closed when job of run is stopped
+func WithStopSignal(ctx context.Context, stop <-chan struct{}) context.Context {
+	return context.WithValue(ctx, stopKey{}, stop)
+}
+
+// returns channel closed when job of run is stopped, nil if run can't be stopped
+func StopSignal(ctx context.Context) <-chan struct{} {
+	stop, _ := ctx.Value(stopKey{}).(<-chan struct{})
+
+	return stop
+}
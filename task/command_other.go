@@ -0,0 +1,16 @@
+//go:build !unix
+
+package task
+
+import (
+	"os/exec"
+)
+
+// process groups aren't supported, cancelling command's context kills its process
+func group(c *exec.Cmd) {
+}
+
+// kills command's process
+func terminate(c *exec.Cmd, finished <-chan struct{}) {
+	c.Process.Kill()
+}
@@ -0,0 +1,125 @@
+//go:build unix
+
+package task
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func runCommand(t *testing.T, ctx context.Context, cmd Command) (CommandResult, error) {
+	task, err := CreateCommand(time.Second, 0, 0, cmd)
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	v, err := task.GetResultFunc()(ctx)
+	res, ok := v.(CommandResult)
+
+	if !ok {
+		t.Fatal("Wrong result of command: ", v)
+	}
+
+	return res, err
+}
+
+func Test_Command_CreateError(t *testing.T) {
+	if _, err := CreateCommand(time.Second, 0, 0, Command{}); err == nil {
+		t.Error("Failed to detect missing command")
+	}
+
+	if _, err := CreateCommand(time.Second, 0, 0, Command{Path: "true", OutputLimit: -1}); err == nil {
+		t.Error("Failed to detect negative output limit")
+	}
+
+	if _, err := CreateCommand(-time.Second, 0, 0, Command{Path: "true"}); err == nil {
+		t.Error("Failed to detect negative period")
+	}
+}
+
+func Test_Command_Output(t *testing.T) {
+	res, err := runCommand(t, context.Background(), Command{
+		Path: "sh",
+		Args: []string{"-c", "echo $GREETING; pwd; echo oops >&2"},
+		Env:  []string{"GREETING=hello"},
+		Dir:  "/",
+	})
+
+	if err != nil {
+		t.Fatal("Failed to run command: ", err)
+	}
+
+	if res.ExitCode != 0 || res.Stdout != "hello\n/\n" || res.Stderr != "oops\n" || res.Truncated {
+		t.Error("Wrong result of command: ", res)
+	}
+}
+
+func Test_Command_ExitCode(t *testing.T) {
+	res, err := runCommand(t, context.Background(), Command{Path: "sh", Args: []string{"-c", "echo broken >&2; exit 3"}})
+
+	var ee *ExitError
+	if !errors.As(err, &ee) || ee.Code != 3 || res.ExitCode != 3 || !strings.Contains(err.Error(), "broken") {
+		t.Error("Failed to map exit code to error: ", err, res)
+	}
+
+	if _, err := runCommand(t, context.Background(), Command{Path: "/nonexistent/command"}); err == nil {
+		t.Error("Failed to detect missing command")
+	}
+}
+
+func Test_Command_OutputLimit(t *testing.T) {
+	res, err := runCommand(t, context.Background(), Command{Path: "sh", Args: []string{"-c", "echo 0123456789"}, OutputLimit: 4})
+
+	if err != nil {
+		t.Fatal("Failed to run command: ", err)
+	}
+
+	if res.Stdout != "0123" || !res.Truncated {
+		t.Error("Failed to limit output: ", res)
+	}
+}
+
+func Test_Command_CancelKillsGroup(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+
+	start := time.Now()
+	_, err := runCommand(t, ctx, Command{Path: "sh", Args: []string{"-c", "sleep 30 & sleep 30"}})
+
+	if err == nil {
+		t.Error("Failed to detect cancelled command")
+	}
+
+	if d := time.Since(start); d > time.Second {
+		t.Error("Failed to kill process group: ", d)
+	}
+}
+
+func Test_Command_StopSignal(t *testing.T) {
+	stop := make(chan struct{})
+	ctx := WithStopSignal(context.Background(), stop)
+
+	go func() {
+		time.Sleep(time.Millisecond * 100)
+		close(stop)
+	}()
+
+	start := time.Now()
+	res, err := runCommand(t, ctx, Command{Path: "sh", Args: []string{"-c", "trap 'echo stopping; exit 0' TERM; sleep 30 & wait"}})
+
+	if d := time.Since(start); d > time.Second {
+		t.Error("Failed to stop command: ", d)
+	}
+
+	if err != nil || res.Stdout != "stopping\n" {
+		t.Error("Failed to stop command gracefully: ", res, err)
+	}
+
+	if StopSignal(context.Background()) != nil {
+		t.Error("Stop signal without stop signal set")
+	}
+}
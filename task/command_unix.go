@@ -0,0 +1,28 @@
+//go:build unix
+
+package task
+
+import (
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// runs command in its own process group, cancelling its context kills the whole group
+func group(c *exec.Cmd) {
+	c.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	c.Cancel = func() error {
+		return syscall.Kill(-c.Process.Pid, syscall.SIGKILL)
+	}
+}
+
+// sends SIGTERM to command's process group, kills it if command isn't finished in time
+func terminate(c *exec.Cmd, finished <-chan struct{}) {
+	syscall.Kill(-c.Process.Pid, syscall.SIGTERM)
+
+	select {
+	case <-time.After(commandStopTimeout):
+		syscall.Kill(-c.Process.Pid, syscall.SIGKILL)
+	case <-finished:
+	}
+}
@@ -0,0 +1,37 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// returned, wrapped in DurationError, when duration of task is not valid
+var ErrInvalidDuration = errors.New("Invalid duration")
+
+// returned when payload of task is requested but task has no payload
+var ErrNoPayload = errors.New("Task has no payload")
+
+// error of duration of task which is less than 0
+type DurationError struct {
+	Field string
+	Value time.Duration
+}
+
+func (e *DurationError) Error() string {
+	return fmt.Sprintf("%v is less than 0: %v", e.Field, e.Value)
+}
+
+// returns ErrInvalidDuration
+func (e *DurationError) Unwrap() error {
+	return ErrInvalidDuration
+}
+
+// returns DurationError if duration d named field is less than 0
+func checkDuration(field string, d time.Duration) error {
+	if d < 0 {
+		return &DurationError{Field: field, Value: d}
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package task
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_Errors_InvalidDuration(t *testing.T) {
+	_, err := Create(time.Second, -time.Second, 0, outer("hello"))
+
+	var de *DurationError
+	if !errors.Is(err, ErrInvalidDuration) || !errors.As(err, &de) || de.Field != "Task time" || de.Value != -time.Second {
+		t.Error("Failed to detect invalid task time: ", err)
+	}
+
+	task, _ := Create(time.Second, time.Second, 0, outer("hello"))
+
+	for _, err := range []error{task.SetPeriod(-1), task.SetDelay(-1), task.SetAlignment(-1), task.SetJitter(-1), task.SetSplay(-1)} {
+		if !errors.Is(err, ErrInvalidDuration) {
+			t.Error("Failed to detect invalid duration: ", err)
+		}
+	}
+
+	if _, err := CreateHTTP(time.Second, time.Second, 0, HTTPRequest{URL: "http://localhost", Timeout: -1}); !errors.Is(err, ErrInvalidDuration) {
+		t.Error("Failed to detect invalid timeout: ", err)
+	}
+}
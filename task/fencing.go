@@ -0,0 +1,20 @@
+package task
+
+import (
+	"context"
+)
+
+type tokenKey struct{}
+
+// returns context of run with fencing token of lease held by run
+func WithFencingToken(ctx context.Context, token int64) context.Context {
+	return context.WithValue(ctx, tokenKey{}, token)
+}
+
+// returns fencing token of lease held by run, token grows with every acquisition of lease,
+// so storage may reject writes with token lower than the last seen one
+func FencingToken(ctx context.Context) (int64, bool) {
+	token, ok := ctx.Value(tokenKey{}).(int64)
+
+	return token, ok
+}
@@ -0,0 +1,182 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+// default limit of response body read by HTTP task
+const DefaultBodyLimit = 64 * 1024
+
+// HTTP request made by task
+type HTTPRequest struct {
+	Method       string
+	URL          string
+	Header       http.Header
+	Body         []byte
+	Timeout      time.Duration
+	ExpectStatus []int
+	Assert       func(body []byte) error
+	BodyLimit    int
+	Client       *http.Client
+}
+
+// result of HTTP request, kept in run history
+type HTTPResult struct {
+	Status    int
+	Latency   time.Duration
+	Body      string
+	Truncated bool
+}
+
+// error of response with unexpected status code
+type StatusError struct {
+	Status   int
+	Expected []int
+}
+
+func (e *StatusError) Error() string {
+	if len(e.Expected) == 0 {
+		return fmt.Sprintf("Unexpected status %d, expected 2xx", e.Status)
+	}
+
+	return fmt.Sprintf("Unexpected status %d, expected one of %v", e.Status, e.Expected)
+}
+
+// creates task which makes HTTP request; response with unexpected status, 2xx by default, or failing assertion fails the run
+func CreateHTTP(period time.Duration, taskTime time.Duration, delay time.Duration, req HTTPRequest) (task Task, err error) {
+	if req.Method == "" {
+		req.Method = http.MethodGet
+	}
+
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return task, fmt.Errorf("URL is not valid: %v", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return task, fmt.Errorf("URL scheme %q is not supported", u.Scheme)
+	}
+
+	if err := checkDuration("Timeout", req.Timeout); err != nil {
+		return task, err
+	}
+
+	if req.BodyLimit < 0 {
+		return task, fmt.Errorf("Body limit is less than 0")
+	}
+
+	if req.BodyLimit == 0 {
+		req.BodyLimit = DefaultBodyLimit
+	}
+
+	if req.Client == nil {
+		req.Client = http.DefaultClient
+	}
+
+	req.Header = req.Header.Clone()
+	req.Body = append([]byte(nil), req.Body...)
+	req.ExpectStatus = append([]int(nil), req.ExpectStatus...)
+
+	return CreateWithResult(period, taskTime, delay, func(ctx context.Context) (interface{}, error) {
+		return req.do(ctx)
+	})
+}
+
+// makes request under ctx and validates response
+func (req HTTPRequest) do(ctx context.Context) (HTTPResult, error) {
+	res := HTTPResult{}
+
+	if req.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, req.Timeout)
+		defer cancel()
+	}
+
+	r, err := http.NewRequestWithContext(ctx, req.Method, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return res, fmt.Errorf("Failed to create request: %v", err)
+	}
+
+	for k, vs := range req.Header {
+		for _, v := range vs {
+			r.Header.Add(k, v)
+		}
+	}
+
+	start := time.Now()
+
+	resp, err := req.Client.Do(r)
+	if err != nil {
+		res.Latency = time.Since(start)
+		return res, fmt.Errorf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body := &limitedBuffer{limit: req.BodyLimit}
+	_, err = io.Copy(body, resp.Body)
+
+	res.Latency = time.Since(start)
+	res.Status = resp.StatusCode
+	res.Body = string(body.data)
+	res.Truncated = body.truncated
+
+	if err != nil {
+		return res, fmt.Errorf("Failed to read response: %v", err)
+	}
+
+	if !req.expected(resp.StatusCode) {
+		return res, &StatusError{Status: resp.StatusCode, Expected: req.ExpectStatus}
+	}
+
+	if req.Assert != nil {
+		if err := req.Assert(body.data); err != nil {
+			return res, fmt.Errorf("Assertion failed: %v", err)
+		}
+	}
+
+	return res, nil
+}
+
+// checks if response status is expected
+func (req HTTPRequest) expected(status int) bool {
+	if len(req.ExpectStatus) == 0 {
+		return status >= 200 && status < 300
+	}
+
+	for _, s := range req.ExpectStatus {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// returns assertion which checks that response body contains s
+func BodyContains(s string) func(body []byte) error {
+	return func(body []byte) error {
+		if !bytes.Contains(body, []byte(s)) {
+			return fmt.Errorf("Body doesn't contain %q", s)
+		}
+
+		return nil
+	}
+}
+
+// returns assertion which checks that response body matches regular expression re
+func BodyMatches(re *regexp.Regexp) func(body []byte) error {
+	return func(body []byte) error {
+		if !re.Match(body) {
+			return fmt.Errorf("Body doesn't match %v", re)
+		}
+
+		return nil
+	}
+}
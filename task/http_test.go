@@ -0,0 +1,131 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func runHTTP(t *testing.T, req HTTPRequest) (HTTPResult, error) {
+	task, err := CreateHTTP(time.Second, 0, 0, req)
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	v, err := task.GetResultFunc()(context.Background())
+	res, ok := v.(HTTPResult)
+
+	if !ok {
+		t.Fatal("Wrong result of request: ", v)
+	}
+
+	return res, err
+}
+
+func server() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/echo":
+			body, _ := io.ReadAll(r.Body)
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte(r.Method + " " + r.Header.Get("X-Token") + " " + string(body)))
+		case "/slow":
+			time.Sleep(time.Millisecond * 200)
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.Write([]byte(`{"status":"ok"}`))
+		}
+	}))
+}
+
+func Test_HTTP_CreateError(t *testing.T) {
+	if _, err := CreateHTTP(time.Second, 0, 0, HTTPRequest{URL: "ftp://example.com"}); err == nil {
+		t.Error("Failed to detect unsupported scheme")
+	}
+
+	if _, err := CreateHTTP(time.Second, 0, 0, HTTPRequest{URL: "http://example.com", Timeout: -time.Second}); err == nil {
+		t.Error("Failed to detect negative timeout")
+	}
+
+	if _, err := CreateHTTP(time.Second, 0, 0, HTTPRequest{URL: "http://example.com", BodyLimit: -1}); err == nil {
+		t.Error("Failed to detect negative body limit")
+	}
+}
+
+func Test_HTTP_Request(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	res, err := runHTTP(t, HTTPRequest{
+		Method: http.MethodPost,
+		URL:    s.URL + "/echo",
+		Header: http.Header{"X-Token": {"secret"}},
+		Body:   []byte("ping"),
+		Assert: BodyContains("ping"),
+	})
+
+	if err != nil {
+		t.Fatal("Failed to make request: ", err)
+	}
+
+	if res.Status != http.StatusOK || res.Body != "POST secret ping" || res.Latency <= 0 {
+		t.Error("Wrong result of request: ", res)
+	}
+}
+
+func Test_HTTP_UnexpectedStatus(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	res, err := runHTTP(t, HTTPRequest{URL: s.URL + "/missing"})
+
+	var se *StatusError
+	if !errors.As(err, &se) || se.Status != http.StatusNotFound || res.Status != http.StatusNotFound {
+		t.Error("Failed to detect unexpected status: ", err, res)
+	}
+
+	if _, err := runHTTP(t, HTTPRequest{URL: s.URL + "/missing", ExpectStatus: []int{http.StatusNotFound}}); err != nil {
+		t.Error("Failed to accept expected status: ", err)
+	}
+
+	if _, err := runHTTP(t, HTTPRequest{URL: s.URL, ExpectStatus: []int{http.StatusCreated}}); err == nil {
+		t.Error("Failed to detect status other than expected")
+	}
+}
+
+func Test_HTTP_Assert(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	if _, err := runHTTP(t, HTTPRequest{URL: s.URL, Assert: BodyMatches(regexp.MustCompile(`"status":\s*"ok"`))}); err != nil {
+		t.Error("Failed to match body: ", err)
+	}
+
+	if _, err := runHTTP(t, HTTPRequest{URL: s.URL, Assert: BodyContains("degraded")}); err == nil {
+		t.Error("Failed to detect failing assertion")
+	}
+
+	res, err := runHTTP(t, HTTPRequest{URL: s.URL, BodyLimit: 4})
+
+	if err != nil || res.Body != `{"st` || !res.Truncated {
+		t.Error("Failed to limit body: ", res, err)
+	}
+}
+
+func Test_HTTP_Timeout(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	res, err := runHTTP(t, HTTPRequest{URL: s.URL + "/slow", Timeout: time.Millisecond * 50})
+
+	if err == nil || res.Latency > time.Millisecond*150 {
+		t.Error("Failed to detect timeout: ", err, res)
+	}
+}
@@ -0,0 +1,159 @@
+package task
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// sets random jitter of task's runs, every run is moved by uniform random offset in [-jitter, jitter]
+func (t *Task) SetJitter(jitter time.Duration) error {
+	if err := checkDuration("Jitter", jitter); err != nil {
+		return err
+	}
+
+	t.jitter = jitter
+	t.jitterPercent = 0
+
+	return nil
+}
+
+// returns task's jitter
+func (t *Task) GetJitter() time.Duration {
+	return t.jitter
+}
+
+// sets random jitter of task's runs as percent of its period
+func (t *Task) SetJitterPercent(percent float64) error {
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("Jitter percent is not in range [0, 100]")
+	}
+
+	t.jitterPercent = percent
+	t.jitter = 0
+
+	return nil
+}
+
+// returns task's jitter as percent of its period
+func (t *Task) GetJitterPercent() float64 {
+	return t.jitterPercent
+}
+
+// sets splay of task's runs, every run of job is moved by the same offset in [0, splay) derived from job's name
+func (t *Task) SetSplay(splay time.Duration) error {
+	if err := checkDuration("Splay", splay); err != nil {
+		return err
+	}
+
+	t.splay = splay
+
+	return nil
+}
+
+// returns task's splay
+func (t *Task) GetSplay() time.Duration {
+	return t.splay
+}
+
+// sets seed of task's jitter, jitter of every run is derived from seed, job's name and planned time of run,
+// so runs of job are moved by the same offsets in every process and can be previewed
+func (t *Task) SetJitterSeed(seed int64) {
+	t.jitterSeed = seed
+	t.seeded = true
+}
+
+// removes seed of task's jitter, jitter is taken from random source of worker
+func (t *Task) ClearJitterSeed() {
+	t.jitterSeed = 0
+	t.seeded = false
+}
+
+// returns if task's jitter is seeded and its seed
+func (t *Task) GetJitterSeed() (int64, bool) {
+	return t.jitterSeed, t.seeded
+}
+
+// returns max jitter of task's runs
+func (t Task) maxJitter() time.Duration {
+	if t.jitterPercent > 0 {
+		jitter := float64(t.period) * t.jitterPercent / 100
+		if jitter >= math.MaxInt64 {
+			return math.MaxInt64
+		}
+
+		return time.Duration(jitter)
+	}
+
+	return t.jitter
+}
+
+// returns the latest offset of run of job with name n from its schedule: job's splay and max jitter;
+// run isn't late until it's later than its planned time with this offset
+func (t Task) MaxOffset(n string) time.Duration {
+	return sum(t.Splay(n), t.maxJitter())
+}
+
+// returns random jitter of task's run taken from r
+func (t Task) Jitter(r *rand.Rand) time.Duration {
+	jitter := t.maxJitter()
+	if jitter <= 0 {
+		return 0
+	}
+
+	if jitter <= (math.MaxInt64-1)/2 {
+		return time.Duration(r.Int63n(int64(2*jitter)+1)) - jitter
+	}
+
+	// range [0, 2*jitter] doesn't fit in int64, take offset from uint64 rejecting values
+	// of its incomplete last span to keep it uniform
+	n := 2*uint64(jitter) + 1
+	v := r.Uint64()
+	for v < -n%n {
+		v = r.Uint64()
+	}
+
+	return time.Duration(v%n - uint64(jitter))
+}
+
+// returns splay of runs of job with name n
+func (t Task) Splay(n string) time.Duration {
+	if t.splay <= 0 {
+		return 0
+	}
+
+	return time.Duration(hash(n) % uint64(t.splay))
+}
+
+// returns offset of run of job with name n planned at planned from its schedule: job's splay and jitter,
+// jitter is taken from r if task's jitter isn't seeded, no jitter if r is nil
+func (t Task) Offset(n string, planned time.Time, r *rand.Rand) time.Duration {
+	if t.seeded {
+		r = rand.New(rand.NewSource(t.jitterSeed ^ int64(hash(n)) ^ planned.UnixNano()))
+	}
+
+	if r == nil {
+		return t.Splay(n)
+	}
+
+	return sum(t.Splay(n), t.Jitter(r))
+}
+
+// returns a+b limited to max duration, b may be negative
+func sum(a, b time.Duration) time.Duration {
+	if b > 0 && a > math.MaxInt64-b {
+		return math.MaxInt64
+	}
+
+	return a + b
+}
+
+// returns hash of job's name
+func hash(n string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(n))
+
+	return h.Sum64()
+}
@@ -0,0 +1,161 @@
+package task
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func Test_Jitter_SetGet(t *testing.T) {
+	task, err := Create(time.Minute, time.Second, 0, outer("hello"))
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	if err := task.SetJitter(-time.Second); err == nil {
+		t.Error("Failed to detect negative jitter")
+	}
+
+	if err := task.SetJitterPercent(101); err == nil {
+		t.Error("Failed to detect wrong jitter percent")
+	}
+
+	if err := task.SetSplay(-time.Second); err == nil {
+		t.Error("Failed to detect negative splay")
+	}
+
+	if err := task.SetJitter(time.Second); err != nil || task.GetJitter() != time.Second {
+		t.Error("Failed to set jitter: ", err)
+	}
+
+	if err := task.SetJitterPercent(10); err != nil || task.GetJitterPercent() != 10 || task.GetJitter() != 0 {
+		t.Error("Failed to set jitter percent: ", err)
+	}
+
+	if err := task.SetSplay(time.Second * 30); err != nil || task.GetSplay() != time.Second*30 {
+		t.Error("Failed to set splay: ", err)
+	}
+}
+
+func Test_Jitter_Range(t *testing.T) {
+	task, err := Create(time.Minute, time.Second, 0, outer("hello"))
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	r := rand.New(rand.NewSource(1))
+
+	if j := task.Jitter(r); j != 0 {
+		t.Error("Jitter without jitter set: ", j)
+	}
+
+	task.SetJitter(time.Second)
+	negative, positive := false, false
+
+	for i := 0; i < 1000; i++ {
+		j := task.Jitter(r)
+		if j < -time.Second || j > time.Second {
+			t.Fatal("Jitter out of range: ", j)
+		}
+
+		negative = negative || j < 0
+		positive = positive || j > 0
+	}
+
+	if !negative || !positive {
+		t.Error("Jitter is not spread around schedule")
+	}
+
+	task.SetJitterPercent(50)
+
+	for i := 0; i < 1000; i++ {
+		if j := task.Jitter(r); j < -time.Second*30 || j > time.Second*30 {
+			t.Fatal("Percent jitter out of range: ", j)
+		}
+	}
+
+	for _, max := range []time.Duration{math.MaxInt64, math.MaxInt64 / 2} {
+		task.SetJitter(max)
+		negative, positive = false, false
+
+		for i := 0; i < 100; i++ {
+			j := task.Jitter(r)
+			if j < -max {
+				t.Fatal("Jitter out of range: ", j)
+			}
+
+			negative = negative || j < 0
+			positive = positive || j > 0
+		}
+
+		if !negative || !positive {
+			t.Error("Max jitter is not spread around schedule: ", max)
+		}
+	}
+
+	long, _ := Create(math.MaxInt64, time.Second, 0, outer("hello"))
+	long.SetJitterPercent(100)
+
+	if j := long.maxJitter(); j != math.MaxInt64 {
+		t.Error("Wrong max jitter of max period: ", j)
+	}
+
+	long.Jitter(r)
+
+	task.SetJitterPercent(50)
+	a, b := rand.New(rand.NewSource(7)), rand.New(rand.NewSource(7))
+
+	for i := 0; i < 10; i++ {
+		if task.Jitter(a) != task.Jitter(b) {
+			t.Fatal("Jitter differs for the same seed")
+		}
+	}
+}
+
+func Test_Jitter_Splay(t *testing.T) {
+	task, err := Create(time.Minute, time.Second, 0, outer("hello"))
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	if s := task.Splay("job"); s != 0 {
+		t.Error("Splay without splay set: ", s)
+	}
+
+	task.SetSplay(time.Minute)
+
+	if task.Splay("job") != task.Splay("job") {
+		t.Error("Splay is not deterministic")
+	}
+
+	seen := map[time.Duration]bool{}
+
+	for _, n := range []string{"a", "b", "c", "d", "e"} {
+		s := task.Splay(n)
+		if s < 0 || s >= time.Minute {
+			t.Error("Splay out of range: ", s)
+		}
+
+		seen[s] = true
+	}
+
+	if len(seen) < 2 {
+		t.Error("Splay is the same for different jobs")
+	}
+
+	task.SetJitter(time.Second)
+
+	if o := task.MaxOffset("job"); o != task.Splay("job")+time.Second {
+		t.Error("Wrong max offset: ", o)
+	}
+
+	task.SetJitter(math.MaxInt64)
+
+	if o := task.MaxOffset("job"); o != math.MaxInt64 {
+		t.Error("Wrong max offset of max jitter: ", o)
+	}
+}
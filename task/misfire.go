@@ -0,0 +1,132 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// default number of the latest missed runs fired by FireAll policy
+const DefaultMisfireLimit = 10
+
+type catchUpKey struct{}
+
+// policy of runs missed by more than misfire threshold, e.g. while process was suspended or previous run overran
+type Misfire int
+
+const (
+	// missed runs are merged into one catch-up run at the latest of them
+	FireOnce Misfire = iota
+	// every missed run is fired as catch-up run, at most misfire limit of the latest ones
+	FireAll
+	// missed runs are skipped, task waits for its next run
+	Skip
+)
+
+func (m Misfire) String() string {
+	switch m {
+	case FireOnce:
+		return "fire once"
+	case FireAll:
+		return "fire all"
+	case Skip:
+		return "skip"
+	}
+
+	return fmt.Sprintf("Misfire(%d)", int(m))
+}
+
+// sets misfire policy of task, FireOnce by default
+func (t *Task) SetMisfire(policy Misfire) error {
+	if policy != FireOnce && policy != FireAll && policy != Skip {
+		return fmt.Errorf("Misfire policy %v is not valid", policy)
+	}
+
+	t.misfire = policy
+
+	return nil
+}
+
+// returns misfire policy of task
+func (t *Task) GetMisfire() Misfire {
+	return t.misfire
+}
+
+// sets misfire threshold of task, run late by threshold or less isn't missed and is fired as usual
+func (t *Task) SetMisfireThreshold(threshold time.Duration) error {
+	if err := checkDuration("Misfire threshold", threshold); err != nil {
+		return err
+	}
+
+	t.misfireThreshold = threshold
+
+	return nil
+}
+
+// returns misfire threshold of task
+func (t *Task) GetMisfireThreshold() time.Duration {
+	return t.misfireThreshold
+}
+
+// sets max number of missed runs fired by FireAll policy
+func (t *Task) SetMisfireLimit(limit int) error {
+	if limit <= 0 {
+		return fmt.Errorf("Misfire limit must be greater than 0")
+	}
+
+	t.misfireLimit = limit
+
+	return nil
+}
+
+// returns max number of missed runs fired by FireAll policy
+func (t *Task) GetMisfireLimit() int {
+	if t.misfireLimit == 0 {
+		return DefaultMisfireLimit
+	}
+
+	return t.misfireLimit
+}
+
+// returns runs planned from next on which are missed at now and must be fired as catch-up runs by task's misfire policy,
+// and time of the first run which isn't missed
+func (t Task) Missed(next time.Time, now time.Time) ([]time.Time, time.Time) {
+	limit := 0
+	switch t.misfire {
+	case FireOnce:
+		limit = 1
+	case FireAll:
+		limit = t.GetMisfireLimit()
+	}
+
+	missed := []time.Time{}
+	for !next.IsZero() && next.Before(now.Add(-t.misfireThreshold)) {
+		n := t.Next(next)
+		if !n.IsZero() && !n.After(next) {
+			return nil, t.Next(now)
+		}
+
+		if limit > 0 {
+			if len(missed) == limit {
+				missed = missed[1:]
+			}
+			missed = append(missed, next)
+		}
+
+		next = n
+	}
+
+	return missed, next
+}
+
+// returns context of run fired to catch up run missed by its job
+func WithCatchUp(ctx context.Context) context.Context {
+	return context.WithValue(ctx, catchUpKey{}, true)
+}
+
+// checks if run catches up run missed by its job
+func IsCatchUp(ctx context.Context) bool {
+	catchUp, _ := ctx.Value(catchUpKey{}).(bool)
+
+	return catchUp
+}
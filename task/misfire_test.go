@@ -0,0 +1,95 @@
+package task
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_Misfire_SetGet(t *testing.T) {
+	task, err := Create(time.Minute, time.Second, 0, outer("hello"))
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	if task.GetMisfire() != FireOnce || task.GetMisfireThreshold() != 0 || task.GetMisfireLimit() != DefaultMisfireLimit {
+		t.Error("Wrong default misfire policy: ", task.GetMisfire(), task.GetMisfireThreshold(), task.GetMisfireLimit())
+	}
+
+	if err := task.SetMisfire(Misfire(7)); err == nil {
+		t.Error("Failed to detect wrong misfire policy")
+	}
+
+	if err := task.SetMisfireThreshold(-time.Second); err == nil {
+		t.Error("Failed to detect negative misfire threshold")
+	}
+
+	if err := task.SetMisfireLimit(0); err == nil {
+		t.Error("Failed to detect wrong misfire limit")
+	}
+
+	task.SetMisfire(Skip)
+	task.SetMisfireThreshold(time.Second)
+	task.SetMisfireLimit(3)
+
+	if task.GetMisfire() != Skip || task.GetMisfireThreshold() != time.Second || task.GetMisfireLimit() != 3 {
+		t.Error("Failed to set misfire policy: ", task.GetMisfire(), task.GetMisfireThreshold(), task.GetMisfireLimit())
+	}
+}
+
+func Test_Misfire_Missed(t *testing.T) {
+	task, _ := Create(time.Minute, time.Second, 0, outer("hello"))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start.Add(time.Minute*5 + time.Second*30)
+
+	missed, next := task.Missed(start, now)
+	if len(missed) != 1 || !missed[0].Equal(start.Add(time.Minute*5)) || !next.Equal(start.Add(time.Minute*6)) {
+		t.Error("Wrong runs of FireOnce: ", missed, next)
+	}
+
+	task.SetMisfire(FireAll)
+	task.SetMisfireLimit(4)
+
+	missed, next = task.Missed(start, now)
+	if len(missed) != 4 || !missed[0].Equal(start.Add(time.Minute*2)) || !missed[3].Equal(start.Add(time.Minute*5)) || !next.Equal(start.Add(time.Minute*6)) {
+		t.Error("Wrong runs of FireAll: ", missed, next)
+	}
+
+	task.SetMisfire(Skip)
+
+	missed, next = task.Missed(start, now)
+	if len(missed) != 0 || !next.Equal(start.Add(time.Minute*6)) {
+		t.Error("Wrong runs of Skip: ", missed, next)
+	}
+
+	task.SetMisfireThreshold(time.Minute)
+
+	missed, next = task.Missed(start, now)
+	if len(missed) != 0 || !next.Equal(start.Add(time.Minute*5)) {
+		t.Error("Run late by less than threshold is missed: ", missed, next)
+	}
+
+	if missed, next = task.Missed(now.Add(time.Second), now); len(missed) != 0 || !next.Equal(now.Add(time.Second)) {
+		t.Error("Future run is missed: ", missed, next)
+	}
+
+	task.SetEndTime(start.Add(time.Minute * 3))
+	task.SetMisfire(FireOnce)
+	task.SetMisfireThreshold(0)
+
+	missed, next = task.Missed(start, now)
+	if len(missed) != 1 || !missed[0].Equal(start.Add(time.Minute*3)) || !next.IsZero() {
+		t.Error("Wrong runs of ended task: ", missed, next)
+	}
+}
+
+func Test_Misfire_CatchUpContext(t *testing.T) {
+	if IsCatchUp(context.Background()) {
+		t.Error("Run is catch-up without flag")
+	}
+
+	if !IsCatchUp(WithCatchUp(context.Background())) {
+		t.Error("Failed to flag catch-up run")
+	}
+}
@@ -0,0 +1,130 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// payload bound to task's function, serializable to JSON
+type payload interface {
+	// returns payload in JSON
+	json() ([]byte, error)
+	// returns new payload decoded from JSON bound to the same function
+	decode(data []byte) (payload, error)
+	// returns task's function called with payload
+	bind() func(ctx context.Context) error
+}
+
+type typedPayload[T any] struct {
+	value T
+	do    func(ctx context.Context, value T) error
+}
+
+func (p typedPayload[T]) json() ([]byte, error) {
+	return json.Marshal(p.value)
+}
+
+func (p typedPayload[T]) decode(data []byte) (payload, error) {
+	var value T
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("Failed to decode payload: %v", err)
+	}
+
+	return typedPayload[T]{value: value, do: p.do}, nil
+}
+
+func (p typedPayload[T]) bind() func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return p.do(ctx, p.value)
+	}
+}
+
+// task which function takes typed payload
+type Typed[T any] struct {
+	Task
+}
+
+// creates task which runs do with payload, payload must be serializable to JSON
+func CreateTyped[T any](period time.Duration, taskTime time.Duration, delay time.Duration, value T, do func(ctx context.Context, value T) error) (task Typed[T], err error) {
+	if do == nil {
+		return task, fmt.Errorf("No function provided")
+	}
+
+	p := typedPayload[T]{value: value, do: do}
+
+	if _, err := p.json(); err != nil {
+		return task, fmt.Errorf("Payload isn't serializable to JSON: %v", err)
+	}
+
+	if task.Task, err = Create(period, taskTime, delay, p.bind()); err != nil {
+		return task, err
+	}
+
+	task.payload = p
+
+	return task, nil
+}
+
+// returns typed task from task if its payload has type T
+func TypedOf[T any](task Task) (Typed[T], bool) {
+	if _, ok := task.payload.(typedPayload[T]); !ok {
+		return Typed[T]{}, false
+	}
+
+	return Typed[T]{Task: task}, true
+}
+
+// returns task's payload
+func (t Typed[T]) Payload() T {
+	p, _ := t.payload.(typedPayload[T])
+
+	return p.value
+}
+
+// sets task's payload
+func (t *Typed[T]) SetPayload(value T) error {
+	p, ok := t.payload.(typedPayload[T])
+	if !ok {
+		return fmt.Errorf("Task has no payload of type %T", value)
+	}
+
+	p.value = value
+
+	if _, err := p.json(); err != nil {
+		return fmt.Errorf("Payload isn't serializable to JSON: %v", err)
+	}
+
+	t.payload = p
+	t.do = p.bind()
+
+	return nil
+}
+
+// returns task's payload in JSON
+func (t *Task) GetPayloadJSON() ([]byte, error) {
+	if t.payload == nil {
+		return nil, ErrNoPayload
+	}
+
+	return t.payload.json()
+}
+
+// sets task's payload from JSON
+func (t *Task) SetPayloadJSON(data []byte) error {
+	if t.payload == nil {
+		return ErrNoPayload
+	}
+
+	p, err := t.payload.decode(data)
+	if err != nil {
+		return err
+	}
+
+	t.payload = p
+	t.do = p.bind()
+
+	return nil
+}
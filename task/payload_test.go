@@ -0,0 +1,123 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type report struct {
+	Name  string
+	Count int
+}
+
+func Test_Payload_CreateTyped(t *testing.T) {
+	got := report{}
+
+	task, err := CreateTyped(time.Second, time.Second, 0, report{Name: "daily", Count: 3}, func(ctx context.Context, r report) error {
+		got = r
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	if err := task.GetDoFunc()(context.Background()); err != nil || got.Name != "daily" || got.Count != 3 {
+		t.Error("Failed to pass payload: ", got, err)
+	}
+
+	if task.Payload().Count != 3 {
+		t.Error("Wrong payload: ", task.Payload())
+	}
+
+	if err := task.SetPayload(report{Name: "weekly", Count: 1}); err != nil {
+		t.Error("Failed to set payload: ", err)
+	}
+
+	task.GetDoFunc()(context.Background())
+
+	if got.Name != "weekly" {
+		t.Error("Failed to pass new payload: ", got)
+	}
+
+	task.Print()
+}
+
+func Test_Payload_CreateTypedError(t *testing.T) {
+	if _, err := CreateTyped[report](time.Second, time.Second, 0, report{}, nil); err == nil {
+		t.Error("Failed to detect nil func")
+	}
+
+	if _, err := CreateTyped(time.Second, time.Second, 0, make(chan int), func(ctx context.Context, c chan int) error { return nil }); err == nil {
+		t.Error("Failed to detect payload not serializable to JSON")
+	}
+
+	if _, err := CreateTyped(-time.Second, time.Second, 0, report{}, func(ctx context.Context, r report) error { return nil }); err == nil {
+		t.Error("Failed to detect negative period")
+	}
+}
+
+func Test_Payload_JSON(t *testing.T) {
+	got := report{}
+
+	typed, err := CreateTyped(time.Second, time.Second, 0, report{Name: "daily", Count: 3}, func(ctx context.Context, r report) error {
+		got = r
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	task := typed.Task
+
+	data, err := task.GetPayloadJSON()
+
+	if err != nil || string(data) != `{"Name":"daily","Count":3}` {
+		t.Error("Wrong payload in JSON: ", string(data), err)
+	}
+
+	edited := task
+
+	if err := edited.SetPayloadJSON([]byte(`{"Name":"edited","Count":5}`)); err != nil {
+		t.Fatal("Failed to set payload from JSON: ", err)
+	}
+
+	edited.GetDoFunc()(context.Background())
+
+	if got.Name != "edited" || got.Count != 5 {
+		t.Error("Failed to pass payload from JSON: ", got)
+	}
+
+	task.GetDoFunc()(context.Background())
+
+	if got.Name != "daily" {
+		t.Error("Payload of copied task is changed: ", got)
+	}
+
+	if err := edited.SetPayloadJSON([]byte(`{"Count":"five"}`)); err == nil {
+		t.Error("Failed to detect wrong JSON")
+	}
+
+	back, ok := TypedOf[report](edited)
+
+	if !ok || back.Payload().Name != "edited" {
+		t.Error("Failed to get typed task: ", back.Payload(), ok)
+	}
+
+	if _, ok := TypedOf[string](edited); ok {
+		t.Error("Failed to detect payload of other type")
+	}
+
+	edited.SetDoFunc(outer("hello"))
+
+	if _, err := edited.GetPayloadJSON(); !errors.Is(err, ErrNoPayload) {
+		t.Error("Failed to drop payload with new func: ", err)
+	}
+
+	if err := edited.SetPayloadJSON(data); !errors.Is(err, ErrNoPayload) {
+		t.Error("Failed to detect task without payload: ", err)
+	}
+}
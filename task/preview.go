@@ -0,0 +1,58 @@
+package task
+
+import "time"
+
+// max number of planned runs looked through while previewing fires of task
+const previewSearchLimit = 10000
+
+// run of job previewed by its task's schedule
+type Fire struct {
+	// time of run by schedule
+	Planned time.Time
+	// time run fires at with splay, jitter and windows of task applied
+	At time.Time
+	// false if run is moved by random jitter which can't be previewed, At is time of run without jitter then
+	Exact bool
+	// reason why run is deferred
+	Reason string
+}
+
+// returns the next count fires of job with name n started at from: runs planned by delay, period or calendar schedule and
+// calendar of task, moved by splay and seeded jitter and deferred by windows; runs skipped by windows aren't returned
+func (t Task) Preview(n string, from time.Time, count int) []Fire {
+	fires := []Fire{}
+	exact := t.seeded || t.maxJitter() <= 0
+
+	next := t.First(from)
+	for runs := 0; len(fires) < count && runs < previewSearchLimit; runs++ {
+		planned := next
+		if planned.IsZero() || (t.maxRuns > 0 && runs >= t.maxRuns) {
+			break
+		}
+
+		// schedule which doesn't advance has no more runs
+		if len(fires) > 0 && !planned.After(fires[len(fires)-1].Planned) {
+			break
+		}
+
+		at := planned.Add(t.Offset(n, planned, nil))
+		next = t.Next(next)
+
+		if at.Before(from) {
+			at = from
+		}
+
+		fire, ok, reason := t.Fit(at)
+		if !ok {
+			continue
+		}
+
+		if fire.After(at) {
+			next = t.After(next, fire)
+		}
+
+		fires = append(fires, Fire{Planned: planned, At: fire, Exact: exact, Reason: reason})
+	}
+
+	return fires
+}
@@ -0,0 +1,93 @@
+package task
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Preview_Jitter(t *testing.T) {
+	task, _ := Create(time.Minute, time.Second, time.Second*30, outer("hello"))
+	task.SetJitter(time.Second * 10)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	fires := task.Preview("job", from, 3)
+	if len(fires) != 3 {
+		t.Fatal("Wrong number of fires: ", fires)
+	}
+
+	for i, f := range fires {
+		if !f.Planned.Equal(from.Add(time.Second*30+time.Minute*time.Duration(i))) || !f.At.Equal(f.Planned) || f.Exact {
+			t.Error("Wrong fire with random jitter: ", f)
+		}
+	}
+
+	task.SetJitterSeed(42)
+	if seed, ok := task.GetJitterSeed(); !ok || seed != 42 {
+		t.Error("Failed to set jitter seed: ", seed, ok)
+	}
+
+	fires, again := task.Preview("job", from, 3), task.Preview("job", from, 3)
+	moved := false
+	for i, f := range fires {
+		if d := f.At.Sub(f.Planned); d < -time.Second*10 || d > time.Second*10 || !f.Exact {
+			t.Error("Wrong fire with seeded jitter: ", f)
+		}
+
+		if !f.At.Equal(again[i].At) || !f.At.Equal(f.Planned.Add(task.Offset("job", f.Planned, nil))) {
+			t.Error("Seeded jitter isn't deterministic: ", f, again[i])
+		}
+
+		moved = moved || !f.At.Equal(f.Planned)
+	}
+
+	if !moved {
+		t.Error("Seeded jitter doesn't move runs")
+	}
+
+	if other := task.Preview("other", from, 3); other[0].At.Equal(fires[0].At) && other[1].At.Equal(fires[1].At) {
+		t.Error("Seeded jitter is the same for different jobs")
+	}
+
+	task.ClearJitterSeed()
+	if _, ok := task.GetJitterSeed(); ok {
+		t.Error("Failed to clear jitter seed")
+	}
+}
+
+func Test_Preview_Windows(t *testing.T) {
+	task, _ := Create(time.Hour, time.Second, 0, outer("hello"))
+	task.SetLocation(time.UTC)
+	task.SetMaxRuns(4)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	night, _ := Weekly(TimeOfDay{Hour: 1}, TimeOfDay{Hour: 3})
+	task.AddBlockedWindow(night)
+
+	fires := task.Preview("job", from, 10)
+	if len(fires) != 4 || !fires[1].At.Equal(from.Add(time.Hour*3)) || !strings.HasPrefix(fires[1].Reason, "Deferred to") || !fires[2].At.Equal(from.Add(time.Hour*4)) {
+		t.Error("Wrong fires deferred by window: ", fires)
+	}
+
+	task.SetWindowAction(WindowSkip)
+
+	fires = task.Preview("job", from, 10)
+	if len(fires) != 2 || !fires[0].At.Equal(from) || !fires[1].At.Equal(from.Add(time.Hour*3)) || fires[1].Reason != "" {
+		t.Error("Wrong fires skipped by window: ", fires)
+	}
+
+	if runs := task.Preview("job", from, 1); len(runs) != 1 {
+		t.Error("Wrong number of fires: ", runs)
+	}
+
+	if runs := task.NextRuns(from, 10); len(runs) != 2 || !runs[0].Equal(fires[0].Planned) || !runs[1].Equal(fires[1].Planned) {
+		t.Error("Next runs differ from fires: ", runs)
+	}
+
+	once, _ := Create(0, time.Second, 0, outer("hello"))
+	once.SetStartTime(from)
+
+	if fires := once.Preview("job", from, 10); len(fires) != 1 || !fires[0].At.Equal(from) {
+		t.Error("Wrong fires of task without period: ", fires)
+	}
+}
@@ -0,0 +1,38 @@
+package task
+
+import "fmt"
+
+// priority of task's runs, runs with higher priority are dispatched first when worker's executor is saturated
+type Priority int
+
+const (
+	PriorityLow      Priority = -10
+	PriorityNormal   Priority = 0
+	PriorityHigh     Priority = 10
+	PriorityCritical Priority = 20
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	case PriorityCritical:
+		return "critical"
+	}
+
+	return fmt.Sprintf("Priority(%d)", int(p))
+}
+
+// sets priority of task's runs, PriorityNormal by default
+func (t *Task) SetPriority(p Priority) {
+	t.priority = p
+}
+
+// returns priority of task's runs
+func (t *Task) GetPriority() Priority {
+	return t.priority
+}
@@ -0,0 +1,28 @@
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_Priority_SetGet(t *testing.T) {
+	task, err := Create(time.Minute, time.Second, 0, outer("hello"))
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	if task.GetPriority() != PriorityNormal {
+		t.Error("Wrong default priority: ", task.GetPriority())
+	}
+
+	task.SetPriority(PriorityCritical)
+
+	if task.GetPriority() != PriorityCritical || task.GetPriority().String() != "critical" {
+		t.Error("Failed to set priority: ", task.GetPriority())
+	}
+
+	if s := Priority(5).String(); s != "Priority(5)" {
+		t.Error("Wrong name of custom priority: ", s)
+	}
+}
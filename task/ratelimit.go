@@ -0,0 +1,78 @@
+package task
+
+import (
+	"fmt"
+	"time"
+)
+
+// action of worker for run exceeding rate limit of task or its group
+type RateAction int
+
+const (
+	// run is delayed until rate limit allows it
+	RateDelay RateAction = iota
+	// run is skipped
+	RateSkip
+)
+
+func (a RateAction) String() string {
+	switch a {
+	case RateDelay:
+		return "delay"
+	case RateSkip:
+		return "skip"
+	}
+
+	return fmt.Sprintf("RateAction(%d)", int(a))
+}
+
+// sets rate limit of task's runs: at most burst runs at once and one more run every interval, zero interval removes limit
+func (t *Task) SetRateLimit(every time.Duration, burst int) error {
+	if err := checkDuration("Rate interval", every); err != nil {
+		return err
+	}
+
+	if every > 0 && burst <= 0 {
+		return fmt.Errorf("Burst must be greater than 0")
+	}
+
+	if every == 0 {
+		burst = 0
+	}
+
+	t.rateEvery = every
+	t.rateBurst = burst
+
+	return nil
+}
+
+// returns interval and burst of rate limit of task, zero interval if task isn't limited
+func (t *Task) GetRateLimit() (time.Duration, int) {
+	return t.rateEvery, t.rateBurst
+}
+
+// sets name of rate limit group of task, runs of all tasks of group share group's limit set on worker; empty name removes group
+func (t *Task) SetRateGroup(group string) {
+	t.rateGroup = group
+}
+
+// returns name of rate limit group of task
+func (t *Task) GetRateGroup() string {
+	return t.rateGroup
+}
+
+// sets action for run exceeding rate limit, RateDelay by default
+func (t *Task) SetRateAction(a RateAction) error {
+	if a != RateDelay && a != RateSkip {
+		return fmt.Errorf("Rate action %v is not valid", a)
+	}
+
+	t.rateAction = a
+
+	return nil
+}
+
+// returns action for run exceeding rate limit
+func (t *Task) GetRateAction() RateAction {
+	return t.rateAction
+}
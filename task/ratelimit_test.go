@@ -0,0 +1,45 @@
+package task
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_RateLimit_SetGet(t *testing.T) {
+	task, err := Create(time.Minute, time.Second, 0, outer("hello"))
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	if every, burst := task.GetRateLimit(); every != 0 || burst != 0 || task.GetRateGroup() != "" || task.GetRateAction() != RateDelay {
+		t.Error("Wrong default rate limit: ", every, burst, task.GetRateGroup(), task.GetRateAction())
+	}
+
+	if err := task.SetRateLimit(-time.Second, 1); !errors.Is(err, ErrInvalidDuration) {
+		t.Error("Failed to detect negative rate interval: ", err)
+	}
+
+	if err := task.SetRateLimit(time.Second, 0); err == nil {
+		t.Error("Failed to detect wrong burst")
+	}
+
+	if err := task.SetRateAction(RateAction(5)); err == nil {
+		t.Error("Failed to detect wrong rate action")
+	}
+
+	task.SetRateLimit(time.Second, 3)
+	task.SetRateGroup("api")
+	task.SetRateAction(RateSkip)
+
+	if every, burst := task.GetRateLimit(); every != time.Second || burst != 3 || task.GetRateGroup() != "api" || task.GetRateAction() != RateSkip {
+		t.Error("Failed to set rate limit: ", every, burst, task.GetRateGroup(), task.GetRateAction())
+	}
+
+	task.SetRateLimit(0, 3)
+
+	if every, burst := task.GetRateLimit(); every != 0 || burst != 0 {
+		t.Error("Failed to remove rate limit: ", every, burst)
+	}
+}
@@ -0,0 +1,107 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type inputKey struct{}
+
+// creates task which function returns result, result is passed to jobs chained to task's job
+func CreateWithResult(period time.Duration, taskTime time.Duration, delay time.Duration, do func(ctx context.Context) (interface{}, error)) (task Task, err error) {
+	if do == nil {
+		return task, fmt.Errorf("No function provided")
+	}
+
+	if task, err = Create(period, taskTime, delay, discard(do)); err != nil {
+		return task, err
+	}
+
+	task.doResult = do
+
+	return task, nil
+}
+
+// sets function of task which returns result
+func (t *Task) SetResultFunc(do func(ctx context.Context) (interface{}, error)) error {
+	if do == nil {
+		return fmt.Errorf("No function provided")
+	}
+
+	t.do = discard(do)
+	t.doResult = do
+	t.payload = nil
+
+	return nil
+}
+
+// returns task func with result, func without result returns nil result
+func (t *Task) GetResultFunc() func(ctx context.Context) (interface{}, error) {
+	if t.doResult != nil {
+		return t.doResult
+	}
+
+	do := t.do
+
+	return func(ctx context.Context) (interface{}, error) {
+		return nil, do(ctx)
+	}
+}
+
+// returns func which drops result of do
+func discard(do func(ctx context.Context) (interface{}, error)) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := do(ctx)
+
+		return err
+	}
+}
+
+// returns context of run with input passed from previous job of chain
+func WithInput(ctx context.Context, input interface{}) context.Context {
+	return context.WithValue(ctx, inputKey{}, input)
+}
+
+// returns input of run: result of previous job of chain or error of failed job for failure handler
+func Input(ctx context.Context) interface{} {
+	return ctx.Value(inputKey{})
+}
+
+// returns input of run if it has type T
+func InputOf[T any](ctx context.Context) (T, bool) {
+	v, ok := Input(ctx).(T)
+
+	return v, ok
+}
+
+// returns func with result for task from func with typed result
+func Produce[T any](do func(ctx context.Context) (T, error)) func(ctx context.Context) (interface{}, error) {
+	return func(ctx context.Context) (interface{}, error) {
+		return do(ctx)
+	}
+}
+
+// returns func for task from func taking typed input, fails if input has other type
+func Consume[T any](do func(ctx context.Context, input T) error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		input, ok := InputOf[T](ctx)
+		if !ok {
+			return fmt.Errorf("Input %T is not %T", Input(ctx), input)
+		}
+
+		return do(ctx, input)
+	}
+}
+
+// returns func with result for task from func taking typed input and returning typed result, fails if input has other type
+func Transform[In any, Out any](do func(ctx context.Context, input In) (Out, error)) func(ctx context.Context) (interface{}, error) {
+	return func(ctx context.Context) (interface{}, error) {
+		input, ok := InputOf[In](ctx)
+		if !ok {
+			return nil, fmt.Errorf("Input %T is not %T", Input(ctx), input)
+		}
+
+		return do(ctx, input)
+	}
+}
@@ -0,0 +1,102 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func Test_Result_CreateWithResult(t *testing.T) {
+	task, err := CreateWithResult(time.Second, time.Second, 0, func(ctx context.Context) (interface{}, error) {
+		return 42, nil
+	})
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	v, err := task.GetResultFunc()(context.Background())
+
+	if err != nil || v != 42 {
+		t.Error("Wrong result of task: ", v, err)
+	}
+
+	if err := task.GetDoFunc()(context.Background()); err != nil {
+		t.Error("Wrong result of task func: ", err)
+	}
+
+	if _, err := CreateWithResult(time.Second, time.Second, 0, nil); err == nil {
+		t.Error("Failed to detect error while creating task with nil func")
+	}
+
+	if err := task.SetResultFunc(nil); err == nil {
+		t.Error("Failed to detect error while setting nil func")
+	}
+
+	task.SetDoFunc(outer("hello"))
+
+	if v, err := task.GetResultFunc()(context.Background()); v != nil || err != nil {
+		t.Error("Failed to drop result func: ", v, err)
+	}
+}
+
+func Test_Result_Input(t *testing.T) {
+	ctx := WithInput(context.Background(), "hello")
+
+	if Input(ctx) != "hello" {
+		t.Error("Wrong input: ", Input(ctx))
+	}
+
+	if v, ok := InputOf[string](ctx); !ok || v != "hello" {
+		t.Error("Wrong typed input: ", v, ok)
+	}
+
+	if _, ok := InputOf[int](ctx); ok {
+		t.Error("Failed to detect input of other type")
+	}
+
+	if Input(context.Background()) != nil {
+		t.Error("Input without input set")
+	}
+}
+
+func Test_Result_TypedHelpers(t *testing.T) {
+	produce := Produce(func(ctx context.Context) (int, error) {
+		return 21, nil
+	})
+
+	double := Transform(func(ctx context.Context, in int) (string, error) {
+		return fmt.Sprint(in * 2), nil
+	})
+
+	got := ""
+	consume := Consume(func(ctx context.Context, in string) error {
+		got = in
+		return nil
+	})
+
+	v, err := produce(context.Background())
+
+	if err != nil {
+		t.Fatal("Failed to produce: ", err)
+	}
+
+	v, err = double(WithInput(context.Background(), v))
+
+	if err != nil {
+		t.Fatal("Failed to transform: ", err)
+	}
+
+	if err := consume(WithInput(context.Background(), v)); err != nil || got != "42" {
+		t.Error("Failed to consume: ", got, err)
+	}
+
+	if _, err := double(WithInput(context.Background(), "21")); err == nil {
+		t.Error("Failed to detect input of wrong type")
+	}
+
+	if err := consume(context.Background()); err == nil {
+		t.Error("Failed to detect missing input")
+	}
+}
@@ -0,0 +1,169 @@
+package task
+
+import (
+	"fmt"
+	"time"
+)
+
+// time of day when calendar schedule runs task
+type TimeOfDay struct {
+	Hour   int
+	Minute int
+	Second int
+}
+
+// calendar schedule: runs task at time of day on chosen weekdays
+type schedule struct {
+	at   TimeOfDay
+	days []time.Weekday
+}
+
+// checks if time of day is valid
+func (d TimeOfDay) valid() bool {
+	return d.Hour >= 0 && d.Hour < 24 && d.Minute >= 0 && d.Minute < 60 && d.Second >= 0 && d.Second < 60
+}
+
+// returns string representation of time of day
+func (d TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d:%02d", d.Hour, d.Minute, d.Second)
+}
+
+// returns string representation of schedule
+func (s *schedule) String() string {
+	if s == nil {
+		return "none"
+	}
+
+	if len(s.days) == 0 {
+		return fmt.Sprintf("daily at %v", s.at)
+	}
+
+	return fmt.Sprintf("at %v on %v", s.at, s.days)
+}
+
+// returns the earliest run of schedule in location loc after tm, or at tm if inclusive
+func (s *schedule) next(tm time.Time, inclusive bool, loc *time.Location) time.Time {
+	l := tm.In(loc)
+	y, m, d := l.Date()
+
+	// one extra day covers run on the same weekday a week later
+	for i := 0; i <= 8; i++ {
+		c := s.on(y, m, d+i, loc)
+
+		if c.IsZero() {
+			continue
+		}
+
+		if c.After(tm) || (inclusive && c.Equal(tm)) {
+			return c
+		}
+	}
+
+	return time.Time{}
+}
+
+// returns run of schedule on date in location loc, zero time if schedule doesn't run on that date
+func (s *schedule) on(y int, m time.Month, d int, loc *time.Location) time.Time {
+	date := time.Date(y, m, d, 12, 0, 0, 0, loc)
+
+	if len(s.days) > 0 {
+		found := false
+		for _, wd := range s.days {
+			if wd == date.Weekday() {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return time.Time{}
+		}
+	}
+
+	y, m, d = date.Date()
+	want := time.Date(y, m, d, s.at.Hour, s.at.Minute, s.at.Second, 0, time.UTC)
+	tm := time.Date(y, m, d, s.at.Hour, s.at.Minute, s.at.Second, 0, loc)
+	wall := time.Date(tm.Year(), tm.Month(), tm.Day(), tm.Hour(), tm.Minute(), tm.Second(), 0, time.UTC)
+
+	start, end := tm.ZoneBounds()
+
+	// time of day is skipped by transition, task runs at the first valid time after the gap
+	if !wall.Equal(want) {
+		if wall.Before(want) {
+			return end
+		}
+
+		return start
+	}
+
+	// time of day is repeated by transition, task runs once at its first occurrence
+	if !start.IsZero() {
+		_, off := tm.Zone()
+		_, prevOff := start.Add(-time.Nanosecond).Zone()
+
+		if prevOff > off {
+			first := tm.Add(-time.Duration(prevOff-off) * time.Second)
+			if first.Before(start) {
+				return first
+			}
+		}
+	}
+
+	return tm
+}
+
+// sets calendar schedule which runs task every day at time of day, period of task is ignored
+func (t *Task) SetDaily(at TimeOfDay) error {
+	return t.SetWeekly(at)
+}
+
+// sets calendar schedule which runs task at time of day on weekdays, no weekdays means every day; period of task is ignored
+func (t *Task) SetWeekly(at TimeOfDay, days ...time.Weekday) error {
+	if !at.valid() {
+		return fmt.Errorf("Time of day %v is not valid", at)
+	}
+
+	for _, wd := range days {
+		if wd < time.Sunday || wd > time.Saturday {
+			return fmt.Errorf("Weekday %v is not valid", wd)
+		}
+	}
+
+	t.schedule = &schedule{at: at, days: append([]time.Weekday(nil), days...)}
+
+	return nil
+}
+
+// removes calendar schedule, task runs by its period
+func (t *Task) ClearSchedule() {
+	t.schedule = nil
+}
+
+// returns if task has calendar schedule and its time of day and weekdays
+func (t *Task) GetSchedule() (at TimeOfDay, days []time.Weekday, ok bool) {
+	if t.schedule == nil {
+		return at, nil, false
+	}
+
+	return t.schedule.at, append([]time.Weekday(nil), t.schedule.days...), true
+}
+
+// sets location of task's calendar schedule, local time is used by default
+func (t *Task) SetLocation(loc *time.Location) error {
+	if loc == nil {
+		return fmt.Errorf("No location provided")
+	}
+
+	t.loc = loc
+
+	return nil
+}
+
+// returns location of task's calendar schedule
+func (t *Task) GetLocation() *time.Location {
+	if t.loc == nil {
+		return time.Local
+	}
+
+	return t.loc
+}
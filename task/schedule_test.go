@@ -0,0 +1,202 @@
+package task
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func location(t *testing.T, name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+
+	if err != nil {
+		t.Fatal("Failed to load location: ", err)
+	}
+
+	return loc
+}
+
+func daily(t *testing.T, at TimeOfDay, loc *time.Location) Task {
+	task, err := Create(time.Hour, time.Second, 0, outer("hello"))
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	if err := task.SetDaily(at); err != nil {
+		t.Fatal("Failed to set daily schedule: ", err)
+	}
+
+	if err := task.SetLocation(loc); err != nil {
+		t.Fatal("Failed to set location: ", err)
+	}
+
+	return task
+}
+
+func Test_Schedule_SetDailyError(t *testing.T) {
+	task, err := Create(time.Hour, time.Second, 0, outer("hello"))
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	if err := task.SetDaily(TimeOfDay{Hour: 24}); err == nil {
+		t.Error("Failed to detect wrong time of day")
+	}
+
+	if err := task.SetWeekly(TimeOfDay{Hour: 9}, time.Weekday(7)); err == nil {
+		t.Error("Failed to detect wrong weekday")
+	}
+
+	if err := task.SetLocation(nil); err == nil {
+		t.Error("Failed to detect nil location")
+	}
+
+	if _, _, ok := task.GetSchedule(); ok {
+		t.Error("Found schedule which wasn't set")
+	}
+}
+
+func Test_Schedule_Daily(t *testing.T) {
+	loc := location(t, "Europe/Berlin")
+	task := daily(t, TimeOfDay{Hour: 9, Minute: 30}, loc)
+
+	if at, days, ok := task.GetSchedule(); !ok || at != (TimeOfDay{Hour: 9, Minute: 30}) || len(days) != 0 {
+		t.Error("Failed to set daily schedule: ", at, days, ok)
+	}
+
+	from := time.Date(2024, 5, 10, 9, 30, 0, 0, loc)
+
+	if first := task.First(from); !first.Equal(from) {
+		t.Error("Wrong first run at time of day: ", first)
+	}
+
+	if first := task.First(from.Add(time.Second)); !first.Equal(from.AddDate(0, 0, 1)) {
+		t.Error("Wrong first run after time of day: ", first)
+	}
+
+	if next := task.Next(from); !next.Equal(from.AddDate(0, 0, 1)) {
+		t.Error("Wrong next run: ", next)
+	}
+
+	task.ClearSchedule()
+
+	if next := task.Next(from); !next.Equal(from.Add(time.Hour)) {
+		t.Error("Failed to clear schedule: ", next)
+	}
+}
+
+func Test_Schedule_Weekly(t *testing.T) {
+	loc := location(t, "America/New_York")
+	task := daily(t, TimeOfDay{Hour: 17}, loc)
+
+	if err := task.SetWeekly(TimeOfDay{Hour: 17}, time.Monday, time.Friday); err != nil {
+		t.Fatal("Failed to set weekly schedule: ", err)
+	}
+
+	// Wednesday
+	tm := time.Date(2024, 5, 8, 18, 0, 0, 0, loc)
+	want := []time.Time{
+		time.Date(2024, 5, 10, 17, 0, 0, 0, loc),
+		time.Date(2024, 5, 13, 17, 0, 0, 0, loc),
+		time.Date(2024, 5, 17, 17, 0, 0, 0, loc),
+		time.Date(2024, 5, 20, 17, 0, 0, 0, loc),
+	}
+
+	for _, w := range want {
+		tm = task.Next(tm)
+		if !tm.Equal(w) {
+			t.Error("Wrong weekly run: ", tm, w)
+		}
+	}
+}
+
+func Test_Schedule_TransitionDates(t *testing.T) {
+	tests := []struct {
+		zone string
+		at   TimeOfDay
+		from time.Time
+		want []time.Time
+	}{
+		// skipped hour runs at the first valid time after the gap
+		{"Europe/Berlin", TimeOfDay{Hour: 2, Minute: 30}, time.Date(2024, 3, 30, 3, 0, 0, 0, time.UTC), []time.Time{
+			time.Date(2024, 3, 31, 1, 0, 0, 0, time.UTC),
+			time.Date(2024, 4, 1, 0, 30, 0, 0, time.UTC),
+		}},
+		// repeated hour runs once at its first occurrence
+		{"Europe/Berlin", TimeOfDay{Hour: 2, Minute: 30}, time.Date(2024, 10, 26, 3, 0, 0, 0, time.UTC), []time.Time{
+			time.Date(2024, 10, 27, 0, 30, 0, 0, time.UTC),
+			time.Date(2024, 10, 28, 1, 30, 0, 0, time.UTC),
+		}},
+		{"America/New_York", TimeOfDay{Hour: 2, Minute: 15}, time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC), []time.Time{
+			time.Date(2024, 3, 10, 7, 0, 0, 0, time.UTC),
+			time.Date(2024, 3, 11, 6, 15, 0, 0, time.UTC),
+		}},
+		{"America/New_York", TimeOfDay{Hour: 1, Minute: 30}, time.Date(2024, 11, 2, 12, 0, 0, 0, time.UTC), []time.Time{
+			time.Date(2024, 11, 3, 5, 30, 0, 0, time.UTC),
+			time.Date(2024, 11, 4, 6, 30, 0, 0, time.UTC),
+		}},
+		// 30 minutes transition
+		{"Australia/Lord_Howe", TimeOfDay{Hour: 2, Minute: 10}, time.Date(2024, 10, 5, 0, 0, 0, 0, time.UTC), []time.Time{
+			time.Date(2024, 10, 5, 15, 30, 0, 0, time.UTC),
+			time.Date(2024, 10, 6, 15, 10, 0, 0, time.UTC),
+		}},
+		{"Australia/Lord_Howe", TimeOfDay{Hour: 1, Minute: 45}, time.Date(2024, 4, 6, 0, 0, 0, 0, time.UTC), []time.Time{
+			time.Date(2024, 4, 6, 14, 45, 0, 0, time.UTC),
+			time.Date(2024, 4, 7, 15, 15, 0, 0, time.UTC),
+		}},
+	}
+
+	for _, tt := range tests {
+		task := daily(t, tt.at, location(t, tt.zone))
+		tm := task.First(tt.from)
+
+		for i, w := range tt.want {
+			if i > 0 {
+				tm = task.Next(tm)
+			}
+
+			if !tm.Equal(w) {
+				t.Error("Wrong run around transition in ", tt.zone, ": ", tm.UTC(), w)
+			}
+		}
+	}
+}
+
+func Test_Schedule_EveryDayOfYear(t *testing.T) {
+	zones := []string{"UTC", "Europe/Berlin", "America/New_York", "America/Santiago", "Australia/Lord_Howe", "Asia/Tehran"}
+
+	for _, zone := range zones {
+		loc := location(t, zone)
+
+		for minute := 0; minute < 24*60; minute += 15 {
+			at := TimeOfDay{Hour: minute / 60, Minute: minute % 60}
+			task := daily(t, at, loc)
+
+			tm := task.First(time.Date(2024, 1, 1, 0, 0, 0, 0, loc).Add(-time.Nanosecond))
+			day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+			for ; day.Year() == 2024; day = day.AddDate(0, 0, 1) {
+				l := tm.In(loc)
+				want := time.Date(day.Year(), day.Month(), day.Day(), at.Hour, at.Minute, 0, 0, time.UTC)
+				wall := time.Date(l.Year(), l.Month(), l.Day(), l.Hour(), l.Minute(), l.Second(), 0, time.UTC)
+
+				if !wall.Equal(want) {
+					// only skipped time of day may run later, at the end of the gap
+					start, _ := tm.ZoneBounds()
+					if !tm.Equal(start) || !wall.After(want) || wall.Sub(want) > 2*time.Hour {
+						t.Fatal("Wrong run in ", zone, " at ", at, ": ", l, " want ", want)
+					}
+				}
+
+				next := task.Next(tm)
+				if next.Sub(tm) < 20*time.Hour {
+					t.Fatal("Double run in ", zone, " at ", at, ": ", l, " and ", next.In(loc))
+				}
+
+				tm = next
+			}
+		}
+	}
+}
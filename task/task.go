@@ -9,16 +9,57 @@ import (
 )
 
 type Task struct {
-	period   time.Duration
-	taskTime time.Duration
-	delay    time.Duration
-	do       func(ctx context.Context) error
+	period     time.Duration
+	taskTime   time.Duration
+	delay      time.Duration
+	do         func(ctx context.Context) error
+	doResult   func(ctx context.Context) (interface{}, error)
+	payload    payload
+	at         time.Time
+	maxRuns    int
+	endTime    time.Time
+	autoDelete bool
+	align      time.Duration
+	schedule   *schedule
+	loc        *time.Location
+
+	jitter           time.Duration
+	jitterPercent    float64
+	jitterSeed       int64
+	seeded           bool
+	splay            time.Duration
+	misfire          Misfire
+	misfireThreshold time.Duration
+	misfireLimit     int
+	allowed          []Window
+	blocked          []Window
+	windowAction     WindowAction
+	calendar         Calendar
+	failureThreshold int
+	coolDown         time.Duration
+	expectedDuration time.Duration
+	maxStaleness     time.Duration
+	abandonOverrun   bool
+	rateEvery        time.Duration
+	rateBurst        int
+	rateGroup        string
+	rateAction       RateAction
+	priority         Priority
+	waitDelay        time.Duration
 }
 
 // creates task
 func Create(period time.Duration, taskTime time.Duration, delay time.Duration, do func(ctx context.Context) error) (task Task, err error) {
-	if period < 0 || taskTime < 0 || delay < 0 {
-		return task, fmt.Errorf("Period, TaskTime or Delay less than 0")
+	if err := checkDuration("Period", period); err != nil {
+		return task, err
+	}
+
+	if err := checkDuration("Task time", taskTime); err != nil {
+		return task, err
+	}
+
+	if err := checkDuration("Delay", delay); err != nil {
+		return task, err
 	}
 
 	if do == nil {
@@ -33,15 +74,183 @@ func Create(period time.Duration, taskTime time.Duration, delay time.Duration, d
 	return task, nil
 }
 
-// prints task's parametres
+// creates task which runs once at time at
+func CreateOnce(at time.Time, taskTime time.Duration, do func(ctx context.Context) error) (task Task, err error) {
+	if at.IsZero() {
+		return task, fmt.Errorf("No start time provided")
+	}
+
+	if task, err = Create(0, taskTime, 0, do); err != nil {
+		return task, err
+	}
+
+	task.at = at
+	task.maxRuns = 1
+
+	return task, nil
+}
+
+// creates task which runs once after delay
+func CreateOnceAfter(delay time.Duration, taskTime time.Duration, do func(ctx context.Context) error) (task Task, err error) {
+	if task, err = Create(0, taskTime, delay, do); err != nil {
+		return task, err
+	}
+
+	task.maxRuns = 1
+
+	return task, nil
+}
+
+// prints task's parametres, parametres other than period, task time and delay are printed only if they are set
 func (t Task) Print() {
-	fmt.Printf("Period: %v; TaskTime: %v; Delay: %v; Do: %v\n", t.period, t.taskTime, t.delay, runtime.FuncForPC(reflect.ValueOf(t.do).Pointer()).Name())
+	fmt.Printf("Period: %v; TaskTime: %v; Delay: %v; ", t.period, t.taskTime, t.delay)
+
+	var payload []byte
+	if t.payload != nil {
+		var err error
+		if payload, err = t.payload.json(); err != nil {
+			payload = []byte(err.Error())
+		}
+	}
+
+	params := []struct {
+		name  string
+		value interface{}
+		set   bool
+	}{
+		{"Payload", string(payload), t.payload != nil},
+		{"At", t.at, !t.at.IsZero()},
+		{"MaxRuns", t.maxRuns, t.maxRuns != 0},
+		{"EndTime", t.endTime, !t.endTime.IsZero()},
+		{"AutoDelete", t.autoDelete, t.autoDelete},
+		{"Align", t.align, t.align != 0},
+		{"Schedule", t.schedule, t.schedule != nil},
+		{"Location", t.loc, t.loc != nil},
+		{"Jitter", t.jitter, t.jitter != 0},
+		{"JitterPercent", t.jitterPercent, t.jitterPercent != 0},
+		{"JitterSeed", t.jitterSeed, t.seeded},
+		{"Splay", t.splay, t.splay != 0},
+		{"Misfire", t.misfire, t.misfire != 0},
+		{"MisfireThreshold", t.misfireThreshold, t.misfireThreshold != 0},
+		{"MisfireLimit", t.misfireLimit, t.misfireLimit != 0},
+		{"Allowed", t.allowed, len(t.allowed) > 0},
+		{"Blocked", t.blocked, len(t.blocked) > 0},
+		{"WindowAction", t.windowAction, t.windowAction != 0},
+		{"Calendar", t.calendar, t.calendar != nil},
+		{"FailureThreshold", t.failureThreshold, t.failureThreshold != 0},
+		{"CoolDown", t.coolDown, t.coolDown != 0},
+		{"ExpectedDuration", t.expectedDuration, t.expectedDuration != 0},
+		{"MaxStaleness", t.maxStaleness, t.maxStaleness != 0},
+		{"AbandonOverrun", t.abandonOverrun, t.abandonOverrun},
+		{"RateLimit", fmt.Sprintf("%v/%v", t.rateBurst, t.rateEvery), t.rateEvery != 0},
+		{"RateGroup", t.rateGroup, t.rateGroup != ""},
+		{"RateAction", t.rateAction, t.rateAction != 0},
+		{"Priority", t.priority, t.priority != 0},
+		{"WaitDelay", t.waitDelay, t.waitDelay != 0},
+	}
+
+	for _, p := range params {
+		if p.set {
+			fmt.Printf("%v: %v; ", p.name, p.value)
+		}
+	}
+
+	fmt.Printf("Do: %v\n", runtime.FuncForPC(reflect.ValueOf(t.do).Pointer()).Name())
+}
+
+// returns time of the first run of task started at from, zero time if task has no runs
+func (t Task) First(from time.Time) time.Time {
+	first := from.Add(t.delay)
+
+	if !t.at.IsZero() {
+		first = t.at
+		if first.Before(from) {
+			first = from
+			if t.period > 0 && t.schedule == nil {
+				// keeps runs on the grid of start time, so every process gets the same run times
+				n := (from.Sub(t.at) + t.period - 1) / t.period
+				first = t.at.Add(n * t.period)
+			}
+		}
+	}
+
+	if t.schedule != nil {
+		first = t.schedule.next(first, true, t.GetLocation())
+	} else {
+		first = t.alignUp(first)
+	}
+
+	first = t.included(first)
+
+	if !t.endTime.IsZero() && first.After(t.endTime) {
+		return time.Time{}
+	}
+
+	return first
+}
+
+// returns time of the run following run planned at prev, zero time if task has no more runs
+func (t Task) Next(prev time.Time) time.Time {
+	next := t.alignUp(prev.Add(t.period))
+	if t.schedule != nil {
+		next = t.schedule.next(prev, false, t.GetLocation())
+	}
+
+	next = t.included(next)
+
+	if !t.endTime.IsZero() && next.After(t.endTime) {
+		return time.Time{}
+	}
+
+	return next
+}
+
+// returns the first run planned from next on which is after tm
+func (t Task) After(next time.Time, tm time.Time) time.Time {
+	for !next.IsZero() && !next.After(tm) {
+		n := t.Next(next)
+		if !n.IsZero() && !n.After(next) {
+			return t.Next(tm)
+		}
+
+		next = n
+	}
+
+	return next
+}
+
+// returns planned times of the next n runs of task started at from previewed by Preview, fewer if task has fewer runs left
+func (t Task) NextRuns(from time.Time, n int) []time.Time {
+	runs := []time.Time{}
+	for _, f := range t.Preview("", from, n) {
+		runs = append(runs, f.Planned)
+	}
+
+	return runs
+}
+
+// rounds tm up to the nearest multiple of task's alignment counted from Unix epoch
+func (t Task) alignUp(tm time.Time) time.Time {
+	if t.align == 0 {
+		return tm
+	}
+
+	tm = tm.Round(0)
+
+	if r := time.Duration(tm.UnixNano() % int64(t.align)); r != 0 {
+		if r < 0 {
+			r += t.align
+		}
+		tm = tm.Add(t.align - r)
+	}
+
+	return tm
 }
 
 // sets task's period
 func (t *Task) SetPeriod(period time.Duration) error {
-	if period < 0 {
-		return fmt.Errorf("Period is less than 0")
+	if err := checkDuration("Period", period); err != nil {
+		return err
 	}
 
 	t.period = period
@@ -54,10 +263,10 @@ func (t *Task) GetPeriod() time.Duration {
 	return t.period
 }
 
-// sets task's time
+// sets task's time, 0 means run's time isn't limited
 func (t *Task) SetTaskTime(taskTime time.Duration) error {
-	if taskTime < 0 {
-		return fmt.Errorf("Task time is less than 0")
+	if err := checkDuration("Task time", taskTime); err != nil {
+		return err
 	}
 
 	t.taskTime = taskTime
@@ -72,8 +281,8 @@ func (t *Task) GetTaskTime() time.Duration {
 
 // sets task's delay
 func (t *Task) SetDelay(delay time.Duration) error {
-	if delay < 0 {
-		return fmt.Errorf("Delay is less than 0")
+	if err := checkDuration("Delay", delay); err != nil {
+		return err
 	}
 
 	t.delay = delay
@@ -93,6 +302,8 @@ func (t *Task) SetDoFunc(do func(ctx context.Context) error) error {
 	}
 
 	t.do = do
+	t.doResult = nil
+	t.payload = nil
 
 	return nil
 }
@@ -101,3 +312,65 @@ func (t *Task) SetDoFunc(do func(ctx context.Context) error) error {
 func (t *Task) GetDoFunc() func(ctx context.Context) error {
 	return t.do
 }
+
+// sets maximum number of runs of task, 0 means no limit
+func (t *Task) SetMaxRuns(maxRuns int) error {
+	if maxRuns < 0 {
+		return fmt.Errorf("Max runs is less than 0")
+	}
+
+	t.maxRuns = maxRuns
+
+	return nil
+}
+
+// returns maximum number of runs of task
+func (t *Task) GetMaxRuns() int {
+	return t.maxRuns
+}
+
+// sets time after which task doesn't run, zero time means no end
+func (t *Task) SetEndTime(endTime time.Time) {
+	t.endTime = endTime
+}
+
+// returns task's end time
+func (t *Task) GetEndTime() time.Time {
+	return t.endTime
+}
+
+// sets if job of task is deleted from job pool after its last run
+func (t *Task) SetAutoDelete(autoDelete bool) {
+	t.autoDelete = autoDelete
+}
+
+// returns if job of task is deleted from job pool after its last run
+func (t *Task) GetAutoDelete() bool {
+	return t.autoDelete
+}
+
+// sets absolute time of task's first run, zero time means task starts after its delay
+func (t *Task) SetStartTime(at time.Time) {
+	t.at = at
+}
+
+// returns absolute time of task's first run
+func (t *Task) GetStartTime() time.Time {
+	return t.at
+}
+
+// sets alignment of task's runs to wall clock boundaries, e.g. 15 minutes runs task at :00, :15, :30 and :45
+func (t *Task) SetAlignment(align time.Duration) error {
+	if err := checkDuration("Alignment", align); err != nil {
+		return err
+	}
+
+	t.align = align
+
+	return nil
+}
+
+// returns alignment of task's runs
+func (t *Task) GetAlignment() time.Duration {
+	return t.align
+}
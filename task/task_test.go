@@ -225,3 +225,193 @@ func Test_Task_SetDoFuncFailure(t *testing.T) {
 		t.Error("Failed to detect error while setting new delay: ", err)
 	}
 }
+
+func Test_Task_CreateOnce(t *testing.T) {
+	foo := outer("hello")
+	at := time.Now().Add(time.Hour)
+
+	task, err := CreateOnce(at, time.Second*3, foo)
+
+	if err != nil {
+		t.Error("Failed to create task: ", err)
+	}
+
+	if task.GetMaxRuns() != 1 || !task.First(time.Now()).Equal(at) {
+		t.Error("Failed to create one-shot task: ", task.GetMaxRuns(), task.First(time.Now()))
+	}
+
+	if _, err := CreateOnce(time.Time{}, time.Second*3, foo); err == nil {
+		t.Error("Failed to detect error while creating task without start time")
+	}
+
+	if _, err := CreateOnce(at, time.Second*3, nil); err == nil {
+		t.Error("Failed to detect error while creating task with nil func")
+	}
+}
+
+func Test_Task_CreateOnceAfter(t *testing.T) {
+	foo := outer("hello")
+
+	task, err := CreateOnceAfter(time.Second*2, time.Second*3, foo)
+
+	if err != nil {
+		t.Error("Failed to create task: ", err)
+	}
+
+	now := time.Now()
+
+	if task.GetMaxRuns() != 1 || !task.First(now).Equal(now.Add(time.Second*2)) {
+		t.Error("Failed to create one-shot task: ", task.GetMaxRuns(), task.First(now))
+	}
+
+	if _, err := CreateOnceAfter(time.Second*(-2), time.Second*3, foo); err == nil {
+		t.Error("Failed to detect error while creating task with negative delay")
+	}
+}
+
+func Test_Task_FirstNext(t *testing.T) {
+	foo := outer("hello")
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	task, err := Create(time.Minute, time.Second*3, time.Second*10, foo)
+
+	if err != nil {
+		t.Error("Failed to create task: ", err)
+	}
+
+	if !task.First(now).Equal(now.Add(time.Second * 10)) {
+		t.Error("Wrong first run: ", task.First(now))
+	}
+
+	if !task.Next(now).Equal(now.Add(time.Minute)) {
+		t.Error("Wrong next run: ", task.Next(now))
+	}
+
+	task.SetEndTime(now.Add(time.Second * 30))
+
+	if task.GetEndTime() != now.Add(time.Second*30) {
+		t.Error("Failed to set end time")
+	}
+
+	if !task.Next(now).IsZero() {
+		t.Error("Failed to stop runs after end time: ", task.Next(now))
+	}
+
+	task.SetEndTime(now.Add(time.Second * 5))
+
+	if !task.First(now).IsZero() {
+		t.Error("Failed to skip first run after end time: ", task.First(now))
+	}
+
+	past, err := CreateOnce(now.Add(-time.Hour), time.Second*3, foo)
+
+	if err != nil {
+		t.Error("Failed to create task: ", err)
+	}
+
+	if !past.First(now).Equal(now) {
+		t.Error("Failed to run one-shot task with past start time immediately: ", past.First(now))
+	}
+}
+
+func Test_Task_SetGetMaxRuns(t *testing.T) {
+	foo := outer("hello")
+
+	task, err := Create(time.Second*3, time.Second*3, time.Second*1, foo)
+
+	if err != nil {
+		t.Error("Failed to create task: ", err)
+	}
+
+	if err := task.SetMaxRuns(3); err != nil {
+		t.Error("Failed to set max runs: ", err)
+	}
+
+	if task.GetMaxRuns() != 3 {
+		t.Error("Failed to set max runs: max runs not the same")
+	}
+
+	if err := task.SetMaxRuns(-1); err == nil {
+		t.Error("Failed to detect error while setting negative max runs")
+	}
+
+	task.SetAutoDelete(true)
+
+	if !task.GetAutoDelete() {
+		t.Error("Failed to set auto delete")
+	}
+}
+
+func Test_Task_SetGetStartTime(t *testing.T) {
+	foo := outer("hello")
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	task, err := Create(time.Minute*10, time.Second*3, time.Second*1, foo)
+
+	if err != nil {
+		t.Error("Failed to create task: ", err)
+	}
+
+	task.SetStartTime(at)
+
+	if task.GetStartTime() != at {
+		t.Error("Failed to set start time")
+	}
+
+	if first := task.First(at.Add(-time.Hour)); !first.Equal(at) {
+		t.Error("Wrong first run before start time: ", first)
+	}
+
+	if first := task.First(at.Add(time.Minute * 25)); !first.Equal(at.Add(time.Minute * 30)) {
+		t.Error("Wrong first run after start time: ", first)
+	}
+
+	if first := task.First(at.Add(time.Minute * 30)); !first.Equal(at.Add(time.Minute * 30)) {
+		t.Error("Wrong first run at grid time: ", first)
+	}
+}
+
+func Test_Task_SetGetAlignment(t *testing.T) {
+	foo := outer("hello")
+
+	task, err := Create(time.Minute*15, time.Second*3, 0, foo)
+
+	if err != nil {
+		t.Error("Failed to create task: ", err)
+	}
+
+	if err := task.SetAlignment(-time.Minute); err == nil {
+		t.Error("Failed to detect error while setting negative alignment")
+	}
+
+	if err := task.SetAlignment(time.Minute * 15); err != nil {
+		t.Error("Failed to set alignment: ", err)
+	}
+
+	if task.GetAlignment() != time.Minute*15 {
+		t.Error("Failed to set alignment: alignment not the same")
+	}
+
+	want := time.Date(2024, 1, 1, 12, 15, 0, 0, time.UTC)
+
+	for _, from := range []time.Time{
+		time.Date(2024, 1, 1, 12, 0, 0, 1, time.UTC),
+		time.Date(2024, 1, 1, 12, 7, 31, 0, time.UTC),
+		time.Date(2024, 1, 1, 12, 15, 0, 0, time.UTC),
+		time.Date(2024, 1, 1, 14, 15, 0, 0, time.FixedZone("UTC+2", 2*60*60)),
+	} {
+		if first := task.First(from); !first.Equal(want) {
+			t.Error("Wrong aligned first run: ", from, first)
+		}
+	}
+
+	if next := task.Next(want); !next.Equal(want.Add(time.Minute * 15)) {
+		t.Error("Wrong aligned next run: ", next)
+	}
+
+	task.SetPeriod(time.Minute * 20)
+
+	if next := task.Next(want); !next.Equal(want.Add(time.Minute * 30)) {
+		t.Error("Wrong aligned next run with period not multiple of alignment: ", next)
+	}
+}
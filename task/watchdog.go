@@ -0,0 +1,45 @@
+package task
+
+import "time"
+
+// sets expected duration of task's runs, watchdog reports longer runs; zero duration isn't watched
+func (t *Task) SetExpectedDuration(d time.Duration) error {
+	if err := checkDuration("Expected duration", d); err != nil {
+		return err
+	}
+
+	t.expectedDuration = d
+
+	return nil
+}
+
+// returns expected duration of task's runs
+func (t *Task) GetExpectedDuration() time.Duration {
+	return t.expectedDuration
+}
+
+// sets max staleness of task, watchdog reports working job without successful run within it; zero staleness isn't watched
+func (t *Task) SetMaxStaleness(d time.Duration) error {
+	if err := checkDuration("Max staleness", d); err != nil {
+		return err
+	}
+
+	t.maxStaleness = d
+
+	return nil
+}
+
+// returns max staleness of task
+func (t *Task) GetMaxStaleness() time.Duration {
+	return t.maxStaleness
+}
+
+// sets if watchdog abandons run which is longer than expected duration, abandoned run is lost and job goes on by its schedule
+func (t *Task) SetAbandonOverrun(abandon bool) {
+	t.abandonOverrun = abandon
+}
+
+// returns if watchdog abandons run which is longer than expected duration
+func (t *Task) GetAbandonOverrun() bool {
+	return t.abandonOverrun
+}
@@ -0,0 +1,31 @@
+package task
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_Watchdog_SetGet(t *testing.T) {
+	task, err := Create(time.Minute, 0, 0, outer("hello"))
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	if err := task.SetExpectedDuration(-time.Second); !errors.Is(err, ErrInvalidDuration) {
+		t.Error("Failed to detect negative expected duration: ", err)
+	}
+
+	if err := task.SetMaxStaleness(-time.Second); !errors.Is(err, ErrInvalidDuration) {
+		t.Error("Failed to detect negative max staleness: ", err)
+	}
+
+	task.SetExpectedDuration(time.Second)
+	task.SetMaxStaleness(time.Hour)
+	task.SetAbandonOverrun(true)
+
+	if task.GetExpectedDuration() != time.Second || task.GetMaxStaleness() != time.Hour || !task.GetAbandonOverrun() {
+		t.Error("Failed to set watchdog: ", task.GetExpectedDuration(), task.GetMaxStaleness(), task.GetAbandonOverrun())
+	}
+}
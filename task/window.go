@@ -0,0 +1,251 @@
+package task
+
+import (
+	"fmt"
+	"time"
+)
+
+// max number of window boundaries crossed while looking for allowed time
+const windowSearchLimit = 1000
+
+// action of worker for run falling outside task's allowed windows or into its blocked windows
+type WindowAction int
+
+const (
+	// run is deferred to the next allowed time
+	WindowDefer WindowAction = iota
+	// run is skipped
+	WindowSkip
+)
+
+func (a WindowAction) String() string {
+	switch a {
+	case WindowDefer:
+		return "defer"
+	case WindowSkip:
+		return "skip"
+	}
+
+	return fmt.Sprintf("WindowAction(%d)", int(a))
+}
+
+// window of time: recurring weekly range between times of day in location of task or ad-hoc range between two instants
+type Window struct {
+	start *schedule
+	end   *schedule
+	from  time.Time
+	to    time.Time
+}
+
+// creates window recurring on weekdays from start till end time of day, no weekdays means every day;
+// window ends next day if end isn't after start, equal start and end make window last the whole day
+func Weekly(start TimeOfDay, end TimeOfDay, days ...time.Weekday) (Window, error) {
+	if !start.valid() || !end.valid() {
+		return Window{}, fmt.Errorf("Window %v-%v is not valid", start, end)
+	}
+
+	for _, wd := range days {
+		if wd < time.Sunday || wd > time.Saturday {
+			return Window{}, fmt.Errorf("Weekday %v is not valid", wd)
+		}
+	}
+
+	return Window{start: &schedule{at: start, days: append([]time.Weekday(nil), days...)}, end: &schedule{at: end}}, nil
+}
+
+// creates window from till to
+func Between(from time.Time, to time.Time) (Window, error) {
+	if from.IsZero() || !to.After(from) {
+		return Window{}, fmt.Errorf("Window %v-%v is not valid", from, to)
+	}
+
+	return Window{from: from, to: to}, nil
+}
+
+// returns string representation of window
+func (w Window) String() string {
+	if w.start == nil {
+		return fmt.Sprintf("%v-%v", w.from, w.to)
+	}
+
+	if len(w.start.days) == 0 {
+		return fmt.Sprintf("daily %v-%v", w.start.at, w.end.at)
+	}
+
+	return fmt.Sprintf("%v-%v on %v", w.start.at, w.end.at, w.start.days)
+}
+
+// returns occurrence of weekly window starting on date in location loc, zero times if window doesn't start on that date
+func (w Window) occurrence(y int, m time.Month, d int, loc *time.Location) (time.Time, time.Time) {
+	start := w.start.on(y, m, d, loc)
+	if start.IsZero() {
+		return start, start
+	}
+
+	if w.end.at.Hour*3600+w.end.at.Minute*60+w.end.at.Second > w.start.at.Hour*3600+w.start.at.Minute*60+w.start.at.Second {
+		return start, w.end.on(y, m, d, loc)
+	}
+
+	return start, w.end.on(y, m, d+1, loc)
+}
+
+// checks if window contains tm
+func (w Window) contains(tm time.Time, loc *time.Location) bool {
+	if w.start == nil {
+		return !tm.Before(w.from) && tm.Before(w.to)
+	}
+
+	y, m, d := tm.In(loc).Date()
+	for i := -1; i <= 0; i++ {
+		start, end := w.occurrence(y, m, d+i, loc)
+		if !start.IsZero() && !tm.Before(start) && tm.Before(end) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// returns the earliest start or end of window after tm, zero time if there is none
+func (w Window) boundary(tm time.Time, loc *time.Location) time.Time {
+	if w.start == nil {
+		switch {
+		case w.from.After(tm):
+			return w.from
+		case w.to.After(tm):
+			return w.to
+		}
+
+		return time.Time{}
+	}
+
+	y, m, d := tm.In(loc).Date()
+
+	// one extra day covers window on the same weekday a week later
+	for i := -1; i <= 8; i++ {
+		start, end := w.occurrence(y, m, d+i, loc)
+
+		if start.After(tm) {
+			return start
+		}
+
+		if !start.IsZero() && end.After(tm) {
+			return end
+		}
+	}
+
+	return time.Time{}
+}
+
+// adds window when task is allowed to run, task with allowed windows runs only inside them
+func (t *Task) AddAllowedWindow(w Window) {
+	t.allowed = append(t.allowed[:len(t.allowed):len(t.allowed)], w)
+}
+
+// adds window when task isn't allowed to run
+func (t *Task) AddBlockedWindow(w Window) {
+	t.blocked = append(t.blocked[:len(t.blocked):len(t.blocked)], w)
+}
+
+// removes all allowed and blocked windows of task
+func (t *Task) ClearWindows() {
+	t.allowed = nil
+	t.blocked = nil
+}
+
+// returns allowed windows of task
+func (t *Task) GetAllowedWindows() []Window {
+	return append([]Window(nil), t.allowed...)
+}
+
+// returns blocked windows of task
+func (t *Task) GetBlockedWindows() []Window {
+	return append([]Window(nil), t.blocked...)
+}
+
+// sets action for run outside of task's windows, WindowDefer by default
+func (t *Task) SetWindowAction(a WindowAction) error {
+	if a != WindowDefer && a != WindowSkip {
+		return fmt.Errorf("Window action %v is not valid", a)
+	}
+
+	t.windowAction = a
+
+	return nil
+}
+
+// returns action for run outside of task's windows
+func (t *Task) GetWindowAction() WindowAction {
+	return t.windowAction
+}
+
+// checks if task may run at tm, returns reason if it may not
+func (t Task) Allowed(tm time.Time) (bool, string) {
+	loc := t.GetLocation()
+
+	for _, w := range t.blocked {
+		if w.contains(tm, loc) {
+			return false, fmt.Sprintf("Run falls into blocked window %v", w)
+		}
+	}
+
+	if len(t.allowed) == 0 {
+		return true, ""
+	}
+
+	for _, w := range t.allowed {
+		if w.contains(tm, loc) {
+			return true, ""
+		}
+	}
+
+	return false, "Run falls outside of allowed windows"
+}
+
+// returns the earliest time from tm on when task may run, zero time if there is none
+func (t Task) NextAllowed(tm time.Time) time.Time {
+	loc := t.GetLocation()
+
+	for i := 0; i < windowSearchLimit; i++ {
+		if ok, _ := t.Allowed(tm); ok {
+			return tm
+		}
+
+		next := time.Time{}
+		for _, ws := range [][]Window{t.allowed, t.blocked} {
+			for _, w := range ws {
+				if b := w.boundary(tm, loc); !b.IsZero() && (next.IsZero() || b.Before(next)) {
+					next = b
+				}
+			}
+		}
+
+		if next.IsZero() {
+			return next
+		}
+
+		tm = next
+	}
+
+	return time.Time{}
+}
+
+// applies task's windows to run firing at tm, returns time run fires at, false if run is skipped
+// and reason if run is deferred or skipped
+func (t Task) Fit(tm time.Time) (time.Time, bool, string) {
+	ok, reason := t.Allowed(tm)
+	if ok {
+		return tm, true, ""
+	}
+
+	if t.windowAction == WindowSkip {
+		return tm, false, reason
+	}
+
+	next := t.NextAllowed(tm)
+	if next.IsZero() {
+		return tm, false, reason + ", no allowed time left"
+	}
+
+	return next, true, fmt.Sprintf("Deferred to %v: %v", next, reason)
+}
@@ -0,0 +1,117 @@
+package task
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Window_Weekly(t *testing.T) {
+	loc := location(t, "America/New_York")
+
+	task, _ := Create(time.Minute, time.Second, 0, outer("hello"))
+	task.SetLocation(loc)
+
+	// business hours on weekdays and overnight maintenance on Sunday
+	business, err := Weekly(TimeOfDay{Hour: 9}, TimeOfDay{Hour: 17}, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+	if err != nil {
+		t.Fatal("Failed to create window: ", err)
+	}
+
+	maintenance, _ := Weekly(TimeOfDay{Hour: 22}, TimeOfDay{Hour: 2}, time.Sunday)
+	task.AddBlockedWindow(business)
+	task.AddBlockedWindow(maintenance)
+
+	cases := []struct {
+		tm      time.Time
+		allowed bool
+	}{
+		{time.Date(2024, 3, 4, 8, 59, 59, 0, loc), true},
+		{time.Date(2024, 3, 4, 9, 0, 0, 0, loc), false},
+		{time.Date(2024, 3, 4, 16, 59, 0, 0, loc), false},
+		{time.Date(2024, 3, 4, 17, 0, 0, 0, loc), true},
+		{time.Date(2024, 3, 2, 12, 0, 0, 0, loc), true},
+		{time.Date(2024, 3, 3, 23, 0, 0, 0, loc), false},
+		{time.Date(2024, 3, 4, 1, 30, 0, 0, loc), false},
+		{time.Date(2024, 3, 4, 2, 0, 0, 0, loc), true},
+		{time.Date(2024, 3, 4, 13, 59, 0, 0, time.UTC), true},
+		{time.Date(2024, 3, 4, 14, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, c := range cases {
+		if ok, reason := task.Allowed(c.tm); ok != c.allowed || (!ok && !strings.Contains(reason, "blocked window")) {
+			t.Error("Wrong window of ", c.tm, ": ", ok, reason)
+		}
+	}
+
+	if next := task.NextAllowed(time.Date(2024, 3, 4, 10, 0, 0, 0, loc)); !next.Equal(time.Date(2024, 3, 4, 17, 0, 0, 0, loc)) {
+		t.Error("Wrong next allowed time: ", next)
+	}
+
+	if next := task.NextAllowed(time.Date(2024, 3, 3, 23, 0, 0, 0, loc)); !next.Equal(time.Date(2024, 3, 4, 2, 0, 0, 0, loc)) {
+		t.Error("Wrong next allowed time after overnight window: ", next)
+	}
+
+	if _, err := Weekly(TimeOfDay{Hour: 24}, TimeOfDay{}); err == nil {
+		t.Error("Failed to detect wrong time of day")
+	}
+
+	if _, err := Weekly(TimeOfDay{}, TimeOfDay{}, time.Weekday(9)); err == nil {
+		t.Error("Failed to detect wrong weekday")
+	}
+}
+
+func Test_Window_Allowed(t *testing.T) {
+	task, _ := Create(time.Minute, time.Second, 0, outer("hello"))
+	task.SetLocation(time.UTC)
+
+	night, _ := Weekly(TimeOfDay{Hour: 1}, TimeOfDay{Hour: 5})
+	task.AddAllowedWindow(night)
+
+	freeze, err := Between(time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC), time.Date(2024, 12, 27, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal("Failed to create window: ", err)
+	}
+	task.AddBlockedWindow(freeze)
+
+	if ok, reason := task.Allowed(time.Date(2024, 12, 1, 12, 0, 0, 0, time.UTC)); ok || !strings.Contains(reason, "outside of allowed windows") {
+		t.Error("Run outside of allowed window is allowed: ", reason)
+	}
+
+	if ok, _ := task.Allowed(time.Date(2024, 12, 1, 3, 0, 0, 0, time.UTC)); !ok {
+		t.Error("Run inside of allowed window isn't allowed")
+	}
+
+	if ok, _ := task.Allowed(time.Date(2024, 12, 25, 3, 0, 0, 0, time.UTC)); ok {
+		t.Error("Blocked window doesn't take precedence over allowed one")
+	}
+
+	if next := task.NextAllowed(time.Date(2024, 12, 23, 6, 0, 0, 0, time.UTC)); !next.Equal(time.Date(2024, 12, 27, 1, 0, 0, 0, time.UTC)) {
+		t.Error("Wrong next allowed time: ", next)
+	}
+
+	if len(task.GetAllowedWindows()) != 1 || len(task.GetBlockedWindows()) != 1 {
+		t.Error("Wrong windows: ", task.GetAllowedWindows(), task.GetBlockedWindows())
+	}
+
+	task.ClearWindows()
+
+	always, _ := Between(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	task.AddAllowedWindow(always)
+
+	if next := task.NextAllowed(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)); !next.IsZero() {
+		t.Error("Found allowed time after the last window: ", next)
+	}
+
+	if _, err := Between(time.Now(), time.Now().Add(-time.Second)); err == nil {
+		t.Error("Failed to detect wrong window")
+	}
+
+	if err := task.SetWindowAction(WindowAction(5)); err == nil {
+		t.Error("Failed to detect wrong window action")
+	}
+
+	if task.SetWindowAction(WindowSkip); task.GetWindowAction() != WindowSkip {
+		t.Error("Failed to set window action")
+	}
+}
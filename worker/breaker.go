@@ -0,0 +1,162 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	t "github.com/vslchnk/goscheduler/task"
+)
+
+// state of circuit breaker of job
+type Circuit string
+
+const (
+	// job runs by its schedule
+	CircuitClosed Circuit = "closed"
+	// job is suspended after consecutive failures
+	CircuitOpen Circuit = "open"
+	// job tries one run after cool-down, its success closes circuit and its failure opens it again
+	CircuitHalfOpen Circuit = "half-open"
+)
+
+// state of circuit breaker of job
+type BreakerState struct {
+	State Circuit
+	// number of consecutive failed runs
+	Failures int
+	// time circuit was opened at
+	Opened time.Time
+	// time of trial run of open circuit, zero if circuit is open until it's reset
+	Retry time.Time
+}
+
+// returns state of circuit breaker of job by its name
+func (w *worker) Breaker(n string) (BreakerState, error) {
+	j, err := w.get(n)
+	if err != nil {
+		return BreakerState{}, err
+	}
+
+	defer j.Unlock()
+	j.Lock()
+
+	return j.breaker, nil
+}
+
+// closes circuit breaker of job by its name, suspended job resumes its runs
+func (w *worker) ResetBreaker(n string) error {
+	j, err := w.get(n)
+	if err != nil {
+		return err
+	}
+
+	j.Lock()
+	state := j.breaker.State
+	j.breaker = BreakerState{State: CircuitClosed}
+	j.Unlock()
+
+	select {
+	case j.reset <- struct{}{}:
+	default:
+	}
+
+	if state != CircuitClosed {
+		w.emit(Event{Type: EventCircuitClosed, Job: n, Time: time.Now(), Message: "Circuit is reset"})
+	}
+
+	return nil
+}
+
+// updates circuit breaker of job by its finished run rec, returns event if state of breaker changes; job must be locked
+func (j *job) trip(n string, task t.Task, rec RunRecord) (Event, bool) {
+	threshold, coolDown := task.GetCircuitBreaker()
+	if threshold == 0 {
+		return Event{}, false
+	}
+
+	b := &j.breaker
+	switch rec.Outcome {
+	case Success:
+		b.Failures = 0
+		if b.State == CircuitClosed {
+			return Event{}, false
+		}
+
+		*b = BreakerState{State: CircuitClosed}
+
+		return Event{Type: EventCircuitClosed, Job: n, Time: time.Now(), Run: rec, Message: "Trial run succeeded"}, true
+	case Failure:
+		b.Failures++
+	default:
+		return Event{}, false
+	}
+
+	if b.State != CircuitHalfOpen && b.Failures < threshold {
+		return Event{}, false
+	}
+
+	b.State, b.Opened, b.Retry = CircuitOpen, time.Now(), time.Time{}
+	if coolDown > 0 {
+		b.Retry = b.Opened.Add(coolDown)
+	}
+
+	return Event{Type: EventCircuitOpen, Job: n, Time: b.Opened, Run: rec, Message: fmt.Sprintf("Job failed %d times in a row", b.Failures)}, true
+}
+
+// checks if circuit breaker of job is open
+func (j *job) open() bool {
+	defer j.Unlock()
+	j.Lock()
+
+	return j.breaker.State == CircuitOpen
+}
+
+// suspends job while its circuit breaker is open, moves breaker to half-open state after cool-down;
+// returns status job ends with and true if job must end
+func (w *worker) suspend(n string, j *job, stop context.Context) (Status, bool) {
+	for {
+		j.Lock()
+		if j.breaker.State != CircuitOpen {
+			j.transition(wtf)
+			j.Unlock()
+
+			return "", false
+		}
+
+		j.transition(sus)
+		retry := j.breaker.Retry
+		j.Unlock()
+
+		var timer *time.Timer
+		var fired <-chan time.Time
+		if !retry.IsZero() {
+			timer = time.NewTimer(time.Until(retry))
+			fired = timer.C
+		}
+
+		select {
+		case <-stop.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+
+			return sss, true
+		case <-j.reset:
+			if timer != nil {
+				timer.Stop()
+			}
+		case <-fired:
+			j.Lock()
+			trial := j.breaker.State == CircuitOpen && j.breaker.Retry.Equal(retry)
+			if trial {
+				j.breaker.State = CircuitHalfOpen
+			}
+			j.Unlock()
+
+			if trial {
+				w.emit(Event{Type: EventCircuitHalfOpen, Job: n, Time: time.Now(), Message: "Cool-down has passed"})
+			}
+		}
+	}
+}
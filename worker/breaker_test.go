@@ -0,0 +1,164 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tk "github.com/vslchnk/goscheduler/task"
+)
+
+// collects events of worker
+type events struct {
+	sync.Mutex
+	list []Event
+}
+
+func (e *events) add(ev Event) {
+	defer e.Unlock()
+	e.Lock()
+
+	e.list = append(e.list, ev)
+}
+
+// returns types of events other than finished runs
+func (e *events) types() []EventType {
+	defer e.Unlock()
+	e.Lock()
+
+	types := []EventType{}
+	for _, ev := range e.list {
+		if ev.Type != EventRunFinished {
+			types = append(types, ev.Type)
+		}
+	}
+
+	return types
+}
+
+func Test_Breaker_CoolDown(t *testing.T) {
+	worker := NewWorker()
+	name := "flaky"
+	var failing atomic.Bool
+	failing.Store(true)
+
+	a, err := tk.Create(time.Millisecond*20, time.Second, 0, func(ctx context.Context) error {
+		if failing.Load() {
+			return fmt.Errorf("Dependency is down")
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	a.SetCircuitBreaker(3, time.Millisecond*100)
+
+	evs := &events{}
+	if err := worker.Subscribe(evs.add); err != nil {
+		t.Fatal("Failed to subscribe to events: ", err)
+	}
+
+	if err := worker.Subscribe(nil); err == nil {
+		t.Error("Failed to detect missing handler")
+	}
+
+	worker.Add(a, name)
+
+	if err := worker.Start(name); err != nil {
+		t.Error("Failed to start worker: ", err)
+	}
+
+	time.Sleep(time.Millisecond * 80)
+
+	b, _ := worker.Breaker(name)
+	if status, _ := worker.Status(name); status != StatusSuspended || b.State != CircuitOpen || b.Failures != 3 || !b.Retry.Equal(b.Opened.Add(time.Millisecond*100)) {
+		t.Error("Failed to open circuit: ", status, b)
+	}
+
+	runs, _ := worker.History(name, HistoryFilter{})
+	if len(runs) != 3 {
+		t.Error("Suspended job runs: ", len(runs))
+	}
+
+	// trial run fails and opens circuit again
+	time.Sleep(time.Millisecond * 100)
+
+	if b, _ := worker.Breaker(name); b.State != CircuitOpen || b.Failures != 4 {
+		t.Error("Failed trial run doesn't open circuit: ", b)
+	}
+
+	failing.Store(false)
+	time.Sleep(time.Millisecond * 150)
+
+	if status, _ := worker.Status(name); !status.working() || status == StatusSuspended {
+		t.Error("Job isn't resumed: ", status)
+	}
+
+	if b, _ := worker.Breaker(name); b.State != CircuitClosed || b.Failures != 0 {
+		t.Error("Successful trial run doesn't close circuit: ", b)
+	}
+
+	worker.Stop(name)
+
+	if fmt.Sprint(evs.types()) != "[circuit open circuit half-open circuit open circuit half-open circuit closed]" {
+		t.Error("Wrong events: ", evs.types())
+	}
+
+	evs.Lock()
+	defer evs.Unlock()
+
+	if len(evs.list) < 4 || evs.list[3].Type != EventCircuitOpen || evs.list[3].Job != name || evs.list[3].Run.Outcome != Failure {
+		t.Error("Wrong event: ", evs.list)
+	}
+}
+
+func Test_Breaker_Reset(t *testing.T) {
+	worker := NewWorker()
+	name := "broken"
+	var runs atomic.Int32
+
+	a, _ := tk.Create(time.Millisecond*20, time.Second, 0, func(ctx context.Context) error {
+		runs.Add(1)
+		return fmt.Errorf("Dependency is down")
+	})
+
+	a.SetCircuitBreaker(2, 0)
+	worker.Add(a, name)
+
+	if err := worker.ResetBreaker("missing"); err == nil {
+		t.Error("Failed to detect missing job")
+	}
+
+	worker.Start(name)
+	time.Sleep(time.Millisecond * 100)
+
+	if b, _ := worker.Breaker(name); b.State != CircuitOpen || !b.Retry.IsZero() || runs.Load() != 2 {
+		t.Error("Failed to open circuit without cool-down: ", b, runs.Load())
+	}
+
+	if err := worker.ResetBreaker(name); err != nil {
+		t.Error("Failed to reset circuit breaker: ", err)
+	}
+
+	time.Sleep(time.Millisecond * 100)
+
+	if b, _ := worker.Breaker(name); b.State != CircuitOpen || runs.Load() != 4 {
+		t.Error("Reset job doesn't run: ", b, runs.Load())
+	}
+
+	if err := worker.Stop(name); err != nil {
+		t.Error("Failed to stop suspended job: ", err)
+	}
+
+	time.Sleep(time.Millisecond * 10)
+
+	if status, _ := worker.Status(name); status != StatusStopped {
+		t.Error("Suspended job isn't stopped: ", status)
+	}
+}
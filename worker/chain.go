@@ -0,0 +1,103 @@
+package worker
+
+import (
+	"fmt"
+)
+
+// chains job next to job n: after every successful run of n, next runs once with its result as input
+func (w *worker) Chain(n string, next string) error {
+	return w.link(n, next, false)
+}
+
+// sets failure handler of job n: after every failed or expired run of n, handler runs once with its error as input
+func (w *worker) OnFailure(n string, handler string) error {
+	return w.link(n, handler, true)
+}
+
+// removes job chained to job n and its failure handler
+func (w *worker) Unchain(n string) error {
+	j, err := w.get(n)
+	if err != nil {
+		return err
+	}
+
+	defer j.Unlock()
+	j.Lock()
+
+	j.next = ""
+	j.onFailure = ""
+
+	return nil
+}
+
+// links job to to job n, checks that links have no cycles
+func (w *worker) link(n string, to string, onFailure bool) error {
+	defer w.Unlock()
+	w.Lock()
+
+	j, ok := w.jobs[n]
+	if !ok {
+		return jobError(n, ErrJobNotFound)
+	}
+
+	if _, ok := w.jobs[to]; !ok {
+		return jobError(to, ErrJobNotFound)
+	}
+
+	if w.reaches(to, n, map[string]bool{}) {
+		return fmt.Errorf("Chaining job %v to job %v makes cycle", to, n)
+	}
+
+	defer j.Unlock()
+	j.Lock()
+
+	if onFailure {
+		j.onFailure = to
+	} else {
+		j.next = to
+	}
+
+	return nil
+}
+
+// checks if job to is reachable from job from by links, worker must be locked
+func (w *worker) reaches(from string, to string, seen map[string]bool) bool {
+	if from == to {
+		return true
+	}
+
+	if seen[from] {
+		return false
+	}
+	seen[from] = true
+
+	j, ok := w.jobs[from]
+	if !ok {
+		return false
+	}
+
+	j.Lock()
+	next, onFailure := j.next, j.onFailure
+	j.Unlock()
+
+	return (next != "" && w.reaches(next, to, seen)) || (onFailure != "" && w.reaches(onFailure, to, seen))
+}
+
+// runs jobs linked to job n after its run rec finished with err
+func (w *worker) chain(n string, rec RunRecord, err error) {
+	j, e := w.get(n)
+	if e != nil {
+		return
+	}
+
+	j.Lock()
+	next, onFailure := j.next, j.onFailure
+	j.Unlock()
+
+	switch {
+	case rec.Outcome == Success && next != "":
+		go w.runOnce(next, rec.Result, rec.RunID)
+	case (rec.Outcome == Failure || rec.Outcome == Timeout) && onFailure != "":
+		go w.runOnce(onFailure, err, rec.RunID)
+	}
+}
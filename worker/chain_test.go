@@ -0,0 +1,168 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	tk "github.com/vslchnk/goscheduler/task"
+)
+
+func Test_Chain_Error(t *testing.T) {
+	worker := NewWorker()
+
+	for _, n := range []string{"a", "b", "c"} {
+		a, err := tk.Create(time.Second, time.Second, 0, outer("hello"))
+
+		if err != nil {
+			t.Fatal("Failed to create task: ", err)
+		}
+
+		worker.Add(a, n)
+	}
+
+	if err := worker.Chain("a", "d"); err == nil {
+		t.Error("Failed to detect missing job")
+	}
+
+	if err := worker.Chain("d", "a"); err == nil {
+		t.Error("Failed to detect missing job")
+	}
+
+	if err := worker.Chain("a", "a"); err == nil {
+		t.Error("Failed to detect chaining job to itself")
+	}
+
+	if err := worker.Chain("a", "b"); err != nil {
+		t.Error("Failed to chain jobs: ", err)
+	}
+
+	if err := worker.OnFailure("b", "c"); err != nil {
+		t.Error("Failed to set failure handler: ", err)
+	}
+
+	if err := worker.Chain("c", "a"); err == nil {
+		t.Error("Failed to detect cycle")
+	}
+
+	if err := worker.Unchain("b"); err != nil {
+		t.Error("Failed to unchain job: ", err)
+	}
+
+	if err := worker.Chain("c", "a"); err != nil {
+		t.Error("Failed to chain jobs after unchain: ", err)
+	}
+
+	if err := worker.Unchain("d"); err == nil {
+		t.Error("Failed to detect missing job")
+	}
+}
+
+func Test_Chain_PassResult(t *testing.T) {
+	worker := NewWorker()
+	got := make(chan string, 1)
+
+	produce, err := tk.CreateWithResult(time.Second, time.Second, 0, tk.Produce(func(ctx context.Context) (int, error) {
+		return 21, nil
+	}))
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	double, err := tk.CreateWithResult(time.Second, time.Second, 0, tk.Transform(func(ctx context.Context, in int) (string, error) {
+		return fmt.Sprint(in * 2), nil
+	}))
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	consume, err := tk.Create(time.Second, time.Second, 0, tk.Consume(func(ctx context.Context, in string) error {
+		got <- in
+		return nil
+	}))
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	produce.SetMaxRuns(1)
+	worker.Add(produce, "produce")
+	worker.Add(double, "double")
+	worker.Add(consume, "consume")
+
+	if err := worker.Chain("produce", "double"); err != nil {
+		t.Fatal("Failed to chain jobs: ", err)
+	}
+
+	if err := worker.Chain("double", "consume"); err != nil {
+		t.Fatal("Failed to chain jobs: ", err)
+	}
+
+	if err := worker.Start("produce"); err != nil {
+		t.Fatal("Failed to start worker: ", err)
+	}
+
+	select {
+	case v := <-got:
+		if v != "42" {
+			t.Error("Wrong result passed by chain: ", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Chained job didn't run")
+	}
+
+	time.Sleep(time.Millisecond * 10)
+
+	first, _ := worker.LastRun("produce", Success)
+	second, _ := worker.LastRun("double", Success)
+	third, _ := worker.LastRun("consume", Success)
+
+	if second.ParentRunID != first.RunID || third.ParentRunID != second.RunID || first.Result != 21 {
+		t.Error("Failed to link chained runs: ", first, second, third)
+	}
+}
+
+func Test_Chain_OnFailure(t *testing.T) {
+	worker := NewWorker()
+	got := make(chan error, 1)
+
+	failing, err := tk.CreateOnceAfter(0, time.Second, func(ctx context.Context) error {
+		return fmt.Errorf("broken")
+	})
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	handler, err := tk.Create(time.Second, time.Second, 0, tk.Consume(func(ctx context.Context, in error) error {
+		got <- in
+		return nil
+	}))
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	worker.Add(failing, "failing")
+	worker.Add(handler, "handler")
+
+	if err := worker.OnFailure("failing", "handler"); err != nil {
+		t.Fatal("Failed to set failure handler: ", err)
+	}
+
+	if err := worker.Start("failing"); err != nil {
+		t.Fatal("Failed to start worker: ", err)
+	}
+
+	select {
+	case err := <-got:
+		if err == nil || err.Error() != "broken" {
+			t.Error("Wrong error passed to failure handler: ", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Failure handler didn't run")
+	}
+}
@@ -0,0 +1,179 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	t "github.com/vslchnk/goscheduler/task"
+)
+
+// time runs of one priority waited for executor
+type WaitStats struct {
+	Runs  int
+	Total time.Duration
+	Max   time.Duration
+}
+
+// returns mean time run waited for executor
+func (s WaitStats) Mean() time.Duration {
+	if s.Runs == 0 {
+		return 0
+	}
+
+	return s.Total / time.Duration(s.Runs)
+}
+
+// state of worker's executor and time runs waited for it by priority of their tasks
+type DispatchStats struct {
+	Limit   int
+	Aging   time.Duration
+	Running int
+	Waiting int
+	Waits   map[t.Priority]WaitStats
+}
+
+// run waiting for executor, ready is closed when run is dispatched
+type waiter struct {
+	priority t.Priority
+	since    time.Time
+	ready    chan struct{}
+}
+
+// executor running limited number of runs at once, waiting runs are dispatched by priority raised by time they wait;
+// it's locked after worker and jobs, never the other way round
+type dispatcher struct {
+	sync.Mutex
+	limit   int
+	aging   time.Duration
+	running int
+	waiting []*waiter
+	waits   map[t.Priority]WaitStats
+}
+
+// creates executor without limit
+func newDispatcher() *dispatcher {
+	return &dispatcher{waits: make(map[t.Priority]WaitStats)}
+}
+
+// limits number of runs of worker's jobs executed at once, zero limit removes it; while limit is reached runs wait and
+// the one with the highest priority is dispatched first, priority of waiting run is raised by one every aging, zero aging disables aging
+func (w *worker) SetMaxConcurrent(limit int, aging time.Duration) error {
+	if limit < 0 {
+		return fmt.Errorf("Limit must not be less than 0")
+	}
+
+	if aging < 0 {
+		return &t.DurationError{Field: "Aging", Value: aging}
+	}
+
+	d := w.dispatcher
+	defer d.Unlock()
+	d.Lock()
+
+	d.limit, d.aging = limit, aging
+	d.dispatch(time.Now())
+
+	return nil
+}
+
+// returns state of executor and time runs waited for it by priority
+func (w *worker) DispatchStats() DispatchStats {
+	d := w.dispatcher
+	defer d.Unlock()
+	d.Lock()
+
+	s := DispatchStats{Limit: d.limit, Aging: d.aging, Running: d.running, Waiting: len(d.waiting), Waits: make(map[t.Priority]WaitStats)}
+	for p, ws := range d.waits {
+		s.Waits[p] = ws
+	}
+
+	return s
+}
+
+// waits for executor to run task, returns func releasing executor and time run waited;
+// returns false if ctx is done before run is dispatched
+func (w *worker) acquire(ctx context.Context, task t.Task) (func(), time.Duration, bool) {
+	d := w.dispatcher
+	wt := &waiter{priority: task.GetPriority(), since: time.Now(), ready: make(chan struct{})}
+
+	d.Lock()
+	d.waiting = append(d.waiting, wt)
+	d.dispatch(wt.since)
+	d.Unlock()
+
+	select {
+	case <-wt.ready:
+	case <-ctx.Done():
+		d.Lock()
+		defer d.Unlock()
+
+		select {
+		case <-wt.ready:
+			// run is dispatched at the same time, so executor is given to another one
+			d.running--
+			d.dispatch(time.Now())
+		default:
+			d.remove(wt)
+		}
+
+		return nil, 0, false
+	}
+
+	release := func() {
+		defer d.Unlock()
+		d.Lock()
+
+		d.running--
+		d.dispatch(time.Now())
+	}
+
+	return release, time.Since(wt.since), true
+}
+
+// dispatches waiting runs with the highest priority while executor isn't saturated, dispatcher must be locked
+func (d *dispatcher) dispatch(now time.Time) {
+	for len(d.waiting) > 0 && (d.limit == 0 || d.running < d.limit) {
+		next := d.waiting[0]
+		for _, wt := range d.waiting[1:] {
+			if p, q := d.priority(wt, now), d.priority(next, now); p > q || (p == q && wt.since.Before(next.since)) {
+				next = wt
+			}
+		}
+
+		d.remove(next)
+		d.running++
+
+		wait := now.Sub(next.since)
+		s := d.waits[next.priority]
+		s.Runs++
+		s.Total += wait
+		if wait > s.Max {
+			s.Max = wait
+		}
+		d.waits[next.priority] = s
+
+		close(next.ready)
+	}
+}
+
+// returns priority of waiting run raised by aging, dispatcher must be locked
+func (d *dispatcher) priority(wt *waiter, now time.Time) t.Priority {
+	if d.aging == 0 {
+		return wt.priority
+	}
+
+	return wt.priority + t.Priority(now.Sub(wt.since)/d.aging)
+}
+
+// removes waiting run, dispatcher must be locked
+func (d *dispatcher) remove(wt *waiter) {
+	for i, v := range d.waiting {
+		if v == wt {
+			d.waiting = append(d.waiting[:i], d.waiting[i+1:]...)
+
+			return
+		}
+	}
+}
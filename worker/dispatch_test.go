@@ -0,0 +1,198 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	tk "github.com/vslchnk/goscheduler/task"
+)
+
+// creates task with priority p
+func prioritized(t *testing.T, p tk.Priority) tk.Task {
+	a, err := tk.Create(time.Second, 0, 0, func(ctx context.Context) error {
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	a.SetPriority(p)
+
+	return a
+}
+
+// waits until n runs wait for executor of worker
+func waiting(w *worker, n int) {
+	for w.DispatchStats().Waiting != n {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func Test_Dispatch_Priority(t *testing.T) {
+	worker := NewWorker()
+
+	if err := worker.SetMaxConcurrent(-1, 0); err == nil {
+		t.Error("Failed to detect wrong limit")
+	}
+
+	if err := worker.SetMaxConcurrent(1, -time.Second); err == nil {
+		t.Error("Failed to detect negative aging")
+	}
+
+	worker.SetMaxConcurrent(1, 0)
+
+	release, wait, ok := worker.acquire(context.Background(), prioritized(t, tk.PriorityNormal))
+	if !ok || wait > time.Millisecond*10 {
+		t.Fatal("Failed to acquire free executor: ", wait, ok)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	order := []tk.Priority{}
+
+	for i, p := range []tk.Priority{tk.PriorityLow, tk.PriorityCritical, tk.PriorityHigh} {
+		wg.Add(1)
+		go func(p tk.Priority) {
+			defer wg.Done()
+
+			release, _, ok := worker.acquire(context.Background(), prioritized(t, p))
+			if !ok {
+				return
+			}
+
+			mu.Lock()
+			order = append(order, p)
+			mu.Unlock()
+
+			release()
+		}(p)
+
+		waiting(worker, i+1)
+	}
+
+	// cancelled run gives up waiting
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan bool)
+	go func() {
+		_, _, ok := worker.acquire(ctx, prioritized(t, tk.PriorityCritical))
+		cancelled <- ok
+	}()
+
+	waiting(worker, 4)
+	cancel()
+
+	if <-cancelled {
+		t.Error("Failed to cancel waiting run")
+	}
+
+	if s := worker.DispatchStats(); s.Limit != 1 || s.Running != 1 || s.Waiting != 3 {
+		t.Error("Wrong state of executor: ", s)
+	}
+
+	time.Sleep(time.Millisecond * 20)
+	release()
+	wg.Wait()
+
+	if len(order) != 3 || order[0] != tk.PriorityCritical || order[1] != tk.PriorityHigh || order[2] != tk.PriorityLow {
+		t.Error("Failed to dispatch runs by priority: ", order)
+	}
+
+	s := worker.DispatchStats()
+	if s.Running != 0 || s.Waiting != 0 {
+		t.Error("Failed to release executor: ", s)
+	}
+
+	if low := s.Waits[tk.PriorityLow]; low.Runs != 1 || low.Max < time.Millisecond*20 || low.Mean() != low.Max {
+		t.Error("Wrong wait time of low priority runs: ", low)
+	}
+
+	if normal := s.Waits[tk.PriorityNormal]; normal.Runs != 1 || normal.Max > time.Millisecond*10 {
+		t.Error("Wrong wait time of normal priority runs: ", normal)
+	}
+
+	if _, ok := s.Waits[tk.PriorityCritical]; !ok {
+		t.Error("Failed to record wait time of critical priority runs")
+	}
+}
+
+func Test_Dispatch_Aging(t *testing.T) {
+	worker := NewWorker()
+	worker.SetMaxConcurrent(1, time.Millisecond*5)
+
+	release, _, _ := worker.acquire(context.Background(), prioritized(t, tk.PriorityNormal))
+
+	first := make(chan tk.Priority, 2)
+	for i, p := range []tk.Priority{tk.PriorityLow, tk.PriorityCritical} {
+		go func(p tk.Priority) {
+			release, _, _ := worker.acquire(context.Background(), prioritized(t, p))
+			first <- p
+			release()
+		}(p)
+
+		waiting(worker, i+1)
+
+		// low priority run waits long enough to outrank critical one
+		time.Sleep(time.Millisecond * 200)
+	}
+
+	release()
+
+	if p := <-first; p != tk.PriorityLow {
+		t.Error("Failed to raise priority of waiting run: ", p)
+	}
+	<-first
+}
+
+func Test_Dispatch_Worker(t *testing.T) {
+	worker := NewWorker()
+	worker.SetMaxConcurrent(1, 0)
+
+	names := []string{"critical", "cleanup", "compaction"}
+	for _, name := range names {
+		a, err := tk.Create(time.Millisecond*10, time.Second, 0, func(ctx context.Context) error {
+			time.Sleep(time.Millisecond * 15)
+
+			return nil
+		})
+
+		if err != nil {
+			t.Fatal("Failed to create task: ", err)
+		}
+
+		if name == "critical" {
+			a.SetPriority(tk.PriorityCritical)
+		}
+
+		worker.Add(a, name)
+		worker.Start(name)
+	}
+
+	time.Sleep(time.Millisecond * 200)
+	worker.StopAll()
+
+	waited := false
+	for _, name := range names {
+		records, _ := worker.History(name, HistoryFilter{})
+		for _, r := range records {
+			if r.QueueWait > 0 && r.Lateness >= r.QueueWait {
+				waited = true
+			}
+		}
+	}
+
+	if !waited {
+		t.Error("Failed to record time runs waited for executor")
+	}
+
+	s := worker.DispatchStats()
+	if s.Waits[tk.PriorityCritical].Runs == 0 || s.Waits[tk.PriorityNormal].Runs == 0 {
+		t.Fatal("Failed to record wait time by priority: ", s.Waits)
+	}
+
+	if s.Waits[tk.PriorityCritical].Mean() >= s.Waits[tk.PriorityNormal].Mean() {
+		t.Error("Failed to dispatch critical runs first: ", s.Waits)
+	}
+}
@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+
+	t "github.com/vslchnk/goscheduler/task"
+)
+
+// errors of operations on jobs, returned wrapped in JobError
+var (
+	ErrJobNotFound       = errors.New("Job not found")
+	ErrJobExists         = errors.New("Job already exists")
+	ErrJobRunning        = errors.New("Job is working")
+	ErrJobNotRunning     = errors.New("Job is not working")
+	ErrJobStopping       = errors.New("Job is already stopping")
+	ErrJobInWorkflow     = errors.New("Job is step of workflow")
+	ErrInvalidTransition = errors.New("Invalid transition of job status")
+	ErrInvalidDuration   = t.ErrInvalidDuration
+)
+
+// error of operation on job, carries name and status of job at the moment of failure
+type JobError struct {
+	Name   string
+	Status Status
+	Err    error
+}
+
+func (e *JobError) Error() string {
+	if e.Status == "" {
+		return fmt.Sprintf("%v: %v", e.Err, e.Name)
+	}
+
+	return fmt.Sprintf("%v: %v, its status: %v", e.Err, e.Name, e.Status)
+}
+
+// returns cause of error
+func (e *JobError) Unwrap() error {
+	return e.Err
+}
+
+// returns JobError of job with name n which has no status
+func jobError(n string, err error) error {
+	return &JobError{Name: n, Err: err}
+}
+
+// returns JobError of job with name n and its current status, job must be locked
+func (j *job) error(n string, err error) error {
+	return &JobError{Name: n, Status: j.status, Err: err}
+}
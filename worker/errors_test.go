@@ -0,0 +1,76 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	tk "github.com/vslchnk/goscheduler/task"
+)
+
+func Test_Errors_Job(t *testing.T) {
+	worker := NewWorker()
+	name := "errors"
+	release := make(chan struct{})
+	defer close(release)
+
+	a, err := tk.Create(time.Hour, 0, 0, func(ctx context.Context) error {
+		<-release
+
+		return nil
+	})
+
+	if err != nil {
+		t.Error("Failed to create task: ", err)
+	}
+
+	if err := worker.Stop(name); !errors.Is(err, ErrJobNotFound) {
+		t.Error("Failed to detect missing job: ", err)
+	}
+
+	if err := worker.Add(a, name); err != nil {
+		t.Error("Failed to add task to worker: ", err)
+	}
+
+	if err := worker.Add(a, name); !errors.Is(err, ErrJobExists) {
+		t.Error("Failed to detect existing job: ", err)
+	}
+
+	err = worker.Kill(name)
+
+	var je *JobError
+	if !errors.Is(err, ErrJobNotRunning) || !errors.As(err, &je) || je.Name != name || je.Status != StatusCreated {
+		t.Error("Failed to detect job which isn't working: ", err)
+	}
+
+	if err := worker.Start(name); err != nil {
+		t.Error("Failed to start worker: ", err)
+	}
+
+	for _, err := range []error{worker.Start(name), worker.Delete(name), worker.ChangeTask(name, a)} {
+		if !errors.Is(err, ErrJobRunning) || !errors.As(err, &je) || je.Status != StatusRunning {
+			t.Error("Failed to detect working job: ", err)
+		}
+	}
+
+	if err := worker.Stop(name); err != nil {
+		t.Error("Failed to stop worker: ", err)
+	}
+
+	if err := worker.Stop(name); !errors.Is(err, ErrJobStopping) || !errors.As(err, &je) || je.Status != StatusStopping {
+		t.Error("Failed to detect stopping job: ", err)
+	}
+
+	if err := worker.Kill(name); err != nil {
+		t.Error("Failed to kill worker: ", err)
+	}
+
+	if err := worker.Start(name); !errors.Is(err, ErrInvalidTransition) && !errors.Is(err, ErrJobNotFound) {
+		t.Error("Failed to detect start of killed job: ", err)
+	}
+
+	if err := worker.SetHistoryLimits(10, -time.Second); !errors.Is(err, ErrInvalidDuration) {
+		t.Error("Failed to detect invalid history max age: ", err)
+	}
+}
@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"fmt"
+	"time"
+)
+
+// type of event of job
+type EventType string
+
+const (
+	EventRunFinished     EventType = "run finished"
+	EventCircuitOpen     EventType = "circuit open"
+	EventCircuitHalfOpen EventType = "circuit half-open"
+	EventCircuitClosed   EventType = "circuit closed"
+	EventRunOverrun      EventType = "run overrun"
+	EventJobStale        EventType = "job stale"
+)
+
+// event of job emitted by worker
+type Event struct {
+	Type EventType
+	Job  string
+	Time time.Time
+	// run which caused event, zero if event isn't caused by run
+	Run     RunRecord
+	Message string
+	// labels of job at the moment of event
+	Labels map[string]string
+}
+
+// subscribes handler to events of all jobs, handler is called in goroutine of job, so it mustn't block
+func (w *worker) Subscribe(handler func(Event)) error {
+	if handler == nil {
+		return fmt.Errorf("No handler provided")
+	}
+
+	defer w.Unlock()
+	w.Lock()
+
+	w.handlers = append(w.handlers[:len(w.handlers):len(w.handlers)], handler)
+
+	return nil
+}
+
+// passes event to handlers subscribed to events, neither worker nor job may be locked
+func (w *worker) emit(e Event) {
+	w.Lock()
+	handlers := w.handlers
+	if j, ok := w.jobs[e.Job]; ok && len(handlers) > 0 {
+		j.Lock()
+		e.Labels = make(map[string]string, len(j.labels))
+		for k, v := range j.labels {
+			e.Labels[k] = v
+		}
+		j.Unlock()
+	}
+	w.Unlock()
+
+	for _, h := range handlers {
+		h(e)
+	}
+}
+
+// emits event of finished run rec of job n and runs jobs linked to it, neither worker nor job may be locked
+func (w *worker) finished(n string, rec RunRecord, err error) {
+	w.emit(Event{Type: EventRunFinished, Job: n, Time: rec.End, Run: rec, Message: rec.Err})
+	w.chain(n, rec, err)
+}
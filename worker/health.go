@@ -0,0 +1,183 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// number of intervals of watchdog loop without its tick after which worker isn't live
+const missedBeats = 3
+
+// interval of polling lock of worker by liveness probe
+const lockPoll = time.Millisecond
+
+// health of job which is failing or stale
+type JobHealth struct {
+	Name        string
+	Status      Status
+	Circuit     Circuit
+	Failures    int
+	Stale       bool
+	Overrun     bool
+	LastSuccess time.Time
+	LastOutcome Outcome
+	LastError   string
+}
+
+// health of worker and its jobs which are failing or stale
+type HealthReport struct {
+	Time      time.Time
+	Live      bool
+	Ready     bool
+	Errors    []string
+	Restoring bool
+	Leader    bool
+	Jobs      []JobHealth
+}
+
+// marks that jobs are being restored from persistent storage, worker isn't ready until restoring is done
+func (w *worker) SetRestoring(restoring bool) {
+	defer w.Unlock()
+	w.Lock()
+
+	w.restoring = restoring
+}
+
+// checks if worker is live: it can be locked within timeout and its watchdog loop, if it's running, ticks
+func (w *worker) Live(timeout time.Duration) error {
+	// worker is polled instead of waiting on its lock, so probes of wedged worker don't leave goroutines behind
+	deadline := time.Now().Add(timeout)
+	for !w.TryLock() {
+		if !time.Now().Before(deadline) {
+			return fmt.Errorf("Worker is locked for more than %v", timeout)
+		}
+
+		time.Sleep(lockPoll)
+	}
+	w.Unlock()
+
+	if interval := time.Duration(w.watchInterval.Load()); interval > 0 {
+		if since := time.Since(time.Unix(0, w.watchBeat.Load())); since > missedBeats*interval {
+			return fmt.Errorf("Watchdog loop hasn't ticked for %v", since.Round(time.Millisecond))
+		}
+	}
+
+	return nil
+}
+
+// checks if worker is ready: its jobs are restored and it holds leadership if it has leader
+func (w *worker) Ready() error {
+	w.Lock()
+	restoring, leader := w.restoring, w.leader
+	w.Unlock()
+
+	if restoring {
+		return fmt.Errorf("Jobs are being restored")
+	}
+
+	if leader != nil {
+		if _, ok := leader.Lease(); !ok {
+			return fmt.Errorf("Leadership isn't acquired")
+		}
+	}
+
+	return nil
+}
+
+// returns report of health of worker, worker which can't be locked within timeout isn't live
+func (w *worker) Health(timeout time.Duration) HealthReport {
+	r := HealthReport{Time: time.Now(), Errors: []string{}, Jobs: []JobHealth{}}
+
+	if err := w.Live(timeout); err != nil {
+		r.Errors = append(r.Errors, err.Error())
+
+		return r
+	}
+	r.Live = true
+
+	if err := w.Ready(); err != nil {
+		r.Errors = append(r.Errors, err.Error())
+	} else {
+		r.Ready = true
+	}
+
+	w.Lock()
+	r.Restoring = w.restoring
+	if w.leader != nil {
+		_, r.Leader = w.leader.Lease()
+	}
+
+	for n, j := range w.jobs {
+		j.Lock()
+		if h, ok := j.health(n, r.Time); ok {
+			r.Jobs = append(r.Jobs, h)
+		}
+		j.Unlock()
+	}
+	w.Unlock()
+
+	sort.Slice(r.Jobs, func(i, k int) bool {
+		return r.Jobs[i].Name < r.Jobs[k].Name
+	})
+
+	return r
+}
+
+// returns health of job at now and true if job is failing or stale, job must be locked
+func (j *job) health(n string, now time.Time) (JobHealth, bool) {
+	_, overrun := j.current()
+	h := JobHealth{Name: n, Status: j.status, Circuit: j.breaker.State, Failures: j.breaker.Failures, Stale: j.stale, Overrun: overrun, LastSuccess: j.lastSuccess}
+
+	if last := j.history.query(HistoryFilter{Limit: 1}, now); len(last) > 0 {
+		h.LastOutcome, h.LastError = last[0].Outcome, last[0].Err
+	}
+
+	failing := h.LastOutcome == Failure || h.LastOutcome == Timeout || h.LastOutcome == Lost || h.Circuit != CircuitClosed
+
+	return h, failing || h.Stale || h.Overrun
+}
+
+// returns handler of liveness probe, it responds with 503 if worker isn't live
+func (w *worker) LivenessHandler(timeout time.Duration) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		probe(rw, w.Live(timeout))
+	})
+}
+
+// returns handler of readiness probe, it responds with 503 if worker isn't ready
+func (w *worker) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		probe(rw, w.Ready())
+	})
+}
+
+// returns handler of JSON health report, it responds with 503 if worker isn't live or ready
+func (w *worker) HealthHandler(timeout time.Duration) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		report := w.Health(timeout)
+
+		rw.Header().Set("Content-Type", "application/json")
+		if !report.Live || !report.Ready {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}
+
+		json.NewEncoder(rw).Encode(report)
+	})
+}
+
+// writes result of probe
+func probe(rw http.ResponseWriter, err error) {
+	rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	if err != nil {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(rw, err)
+
+		return
+	}
+
+	fmt.Fprintln(rw, "ok")
+}
@@ -0,0 +1,157 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/vslchnk/goscheduler/lock"
+	tk "github.com/vslchnk/goscheduler/task"
+)
+
+// returns status code of response of handler
+func code(h http.Handler) int {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	return rec.Code
+}
+
+func Test_Health_Liveness(t *testing.T) {
+	worker := NewWorker()
+	live := worker.LivenessHandler(time.Millisecond * 20)
+
+	if c := code(live); c != http.StatusOK {
+		t.Error("Worker isn't live: ", c)
+	}
+
+	worker.Lock()
+	if c := code(live); c != http.StatusServiceUnavailable {
+		t.Error("Locked worker is live: ", c)
+	}
+
+	// probes of wedged worker don't leak goroutines
+	before := runtime.NumGoroutine()
+	for i := 0; i < 10; i++ {
+		worker.Live(time.Millisecond)
+	}
+
+	if after := runtime.NumGoroutine(); after > before {
+		t.Error("Probes of locked worker leak goroutines: ", before, after)
+	}
+	worker.Unlock()
+
+	a, _ := tk.Create(time.Minute, time.Second, 0, outer("hello"))
+	worker.Add(a, "job")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go worker.Watch(ctx, time.Millisecond*5)
+
+	time.Sleep(time.Millisecond * 20)
+	if err := worker.Live(time.Millisecond * 20); err != nil {
+		t.Error("Worker with watchdog loop isn't live: ", err)
+	}
+
+	// watchdog loop is stuck on locked job
+	j, _ := worker.get("job")
+	j.Lock()
+	time.Sleep(time.Millisecond * 40)
+	err := worker.Live(time.Millisecond * 20)
+	j.Unlock()
+
+	if err == nil {
+		t.Error("Worker with stuck watchdog loop is live")
+	}
+}
+
+func Test_Health_Readiness(t *testing.T) {
+	worker := NewWorker()
+	ready := worker.ReadinessHandler()
+
+	worker.SetRestoring(true)
+	if c := code(ready); c != http.StatusServiceUnavailable {
+		t.Error("Worker restoring jobs is ready: ", c)
+	}
+
+	worker.SetRestoring(false)
+	if c := code(ready); c != http.StatusOK {
+		t.Error("Worker isn't ready: ", c)
+	}
+
+	l, err := lock.NewFileLocker(t.TempDir(), "a")
+	if err != nil {
+		t.Fatal("Failed to create locker: ", err)
+	}
+
+	ld, _ := lock.NewLeader(l, "leader", time.Millisecond*30)
+	worker.SetLeader(ld)
+
+	if err := worker.Ready(); err == nil {
+		t.Error("Worker without leadership is ready")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go ld.Run(ctx)
+
+	time.Sleep(time.Millisecond * 20)
+	if c := code(ready); c != http.StatusOK {
+		t.Error("Leader isn't ready: ", c)
+	}
+}
+
+func Test_Health_Report(t *testing.T) {
+	worker := NewWorker()
+
+	for _, n := range []string{"healthy", "failing", "stale"} {
+		err := error(nil)
+		if n != "healthy" {
+			err = fmt.Errorf("Failed")
+		}
+
+		a, _ := tk.CreateOnceAfter(0, time.Second, func(ctx context.Context) error {
+			return err
+		})
+
+		if n == "stale" {
+			a, _ = tk.Create(time.Minute, time.Second, time.Minute, func(ctx context.Context) error {
+				return nil
+			})
+			a.SetMaxStaleness(time.Millisecond * 10)
+		}
+
+		worker.Add(a, n)
+		worker.Start(n)
+	}
+
+	time.Sleep(time.Millisecond * 30)
+	worker.watch(time.Now())
+
+	rec := httptest.NewRecorder()
+	worker.HealthHandler(time.Second).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	report := HealthReport{}
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil || rec.Code != http.StatusOK {
+		t.Fatal("Failed to get health report: ", rec.Code, err)
+	}
+
+	if !report.Live || !report.Ready || len(report.Jobs) != 2 {
+		t.Fatal("Wrong health report: ", report)
+	}
+
+	if f := report.Jobs[0]; f.Name != "failing" || f.LastOutcome != Failure || f.LastError != "Failed" {
+		t.Error("Wrong health of failing job: ", f)
+	}
+
+	if s := report.Jobs[1]; s.Name != "stale" || !s.Stale || !s.Status.working() {
+		t.Error("Wrong health of stale job: ", s)
+	}
+
+	worker.Kill("stale")
+}
@@ -0,0 +1,228 @@
+package worker
+
+import (
+	"fmt"
+	"time"
+
+	t "github.com/vslchnk/goscheduler/task"
+)
+
+type Outcome string
+
+const (
+	Success    Outcome = "success"
+	Failure    Outcome = "failure"
+	Timeout    Outcome = "timeout"
+	Killed     Outcome = "killed"
+	Enqueued   Outcome = "enqueued"
+	Blocked    Outcome = "blocked"
+	Lost       Outcome = "lost"
+	Throttled  Outcome = "throttled"
+	LockFailed Outcome = "lock failed"
+)
+
+// default limits of run history of each job
+const (
+	DefaultHistorySize   = 100
+	DefaultHistoryMaxAge = 0
+)
+
+// describes one execution of job's task
+type RunRecord struct {
+	RunID        string
+	Job          string
+	PlannedStart time.Time
+	Start        time.Time
+	End          time.Time
+	Duration     time.Duration
+	Outcome      Outcome
+	Err          string
+	Attempt      int
+	ParentRunID  string
+	Result       interface{}
+	CatchUp      bool
+	Reason       string
+	// time run started after its planned start, e.g. while it was delayed by rate limit
+	Lateness time.Duration
+	// time run waited for worker's executor
+	QueueWait time.Duration
+}
+
+// filters run records, zero fields match everything
+type HistoryFilter struct {
+	Outcome Outcome
+	From    time.Time
+	To      time.Time
+	Offset  int
+	Limit   int
+}
+
+// ring buffer of job's run records limited by size and age
+type history struct {
+	records []RunRecord
+	start   int
+	count   int
+	maxAge  time.Duration
+}
+
+// creates history with size and maxAge limits, zero maxAge means no age limit
+func newHistory(size int, maxAge time.Duration) (*history, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("History size must be greater than 0")
+	}
+
+	if maxAge < 0 {
+		return nil, &t.DurationError{Field: "History max age", Value: maxAge}
+	}
+
+	return &history{records: make([]RunRecord, size), maxAge: maxAge}, nil
+}
+
+// adds record to history, overwriting the oldest one if history is full
+func (h *history) add(r RunRecord) {
+	if h.count < len(h.records) {
+		h.records[(h.start+h.count)%len(h.records)] = r
+		h.count++
+	} else {
+		h.records[h.start] = r
+		h.start = (h.start + 1) % len(h.records)
+	}
+
+	h.prune(r.End)
+}
+
+// removes records which ended more than maxAge before now
+func (h *history) prune(now time.Time) {
+	if h.maxAge == 0 {
+		return
+	}
+
+	for h.count > 0 && now.Sub(h.records[h.start].End) > h.maxAge {
+		h.records[h.start] = RunRecord{}
+		h.start = (h.start + 1) % len(h.records)
+		h.count--
+	}
+}
+
+// changes limits of history keeping the newest records
+func (h *history) resize(size int, maxAge time.Duration) error {
+	nh, err := newHistory(size, maxAge)
+	if err != nil {
+		return err
+	}
+
+	from := 0
+	if h.count > size {
+		from = h.count - size
+	}
+
+	for i := from; i < h.count; i++ {
+		nh.add(h.records[(h.start+i)%len(h.records)])
+	}
+
+	*h = *nh
+
+	return nil
+}
+
+// returns records matching filter, newest first
+func (h *history) query(f HistoryFilter, now time.Time) []RunRecord {
+	h.prune(now)
+
+	res := []RunRecord{}
+	skipped := 0
+
+	for i := h.count - 1; i >= 0; i-- {
+		r := h.records[(h.start+i)%len(h.records)]
+
+		if f.Outcome != "" && r.Outcome != f.Outcome {
+			continue
+		}
+
+		if !f.From.IsZero() && r.Start.Before(f.From) {
+			continue
+		}
+
+		if !f.To.IsZero() && !r.Start.Before(f.To) {
+			continue
+		}
+
+		if skipped < f.Offset {
+			skipped++
+			continue
+		}
+
+		res = append(res, r)
+
+		if f.Limit > 0 && len(res) == f.Limit {
+			break
+		}
+	}
+
+	return res
+}
+
+// sets history limits for jobs added later and for every job in pool
+func (w *worker) SetHistoryLimits(size int, maxAge time.Duration) error {
+	if _, err := newHistory(size, maxAge); err != nil {
+		return err
+	}
+
+	defer w.Unlock()
+	w.Lock()
+
+	w.historySize = size
+	w.historyMaxAge = maxAge
+
+	for _, j := range w.jobs {
+		j.Lock()
+		j.history.resize(size, maxAge)
+		j.Unlock()
+	}
+
+	return nil
+}
+
+// sets history limits of job by its name
+func (w *worker) SetJobHistoryLimits(n string, size int, maxAge time.Duration) error {
+	j, err := w.get(n)
+	if err != nil {
+		return err
+	}
+
+	defer j.Unlock()
+	j.Lock()
+
+	return j.history.resize(size, maxAge)
+}
+
+// returns run records of job matching filter, newest first
+func (w *worker) History(n string, f HistoryFilter) ([]RunRecord, error) {
+	if f.Offset < 0 || f.Limit < 0 {
+		return nil, fmt.Errorf("Offset or Limit less than 0")
+	}
+
+	j, err := w.get(n)
+	if err != nil {
+		return nil, err
+	}
+
+	defer j.Unlock()
+	j.Lock()
+
+	return j.history.query(f, time.Now()), nil
+}
+
+// returns the latest run record of job with outcome o, empty o matches every outcome
+func (w *worker) LastRun(n string, o Outcome) (RunRecord, error) {
+	rs, err := w.History(n, HistoryFilter{Outcome: o, Limit: 1})
+	if err != nil {
+		return RunRecord{}, err
+	}
+
+	if len(rs) == 0 {
+		return RunRecord{}, fmt.Errorf("No runs of job %v with outcome %q in history", n, o)
+	}
+
+	return rs[0], nil
+}
@@ -0,0 +1,227 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	tk "github.com/vslchnk/goscheduler/task"
+)
+
+func record(id int, o Outcome, start time.Time) RunRecord {
+	return RunRecord{RunID: fmt.Sprintf("job-%d", id), Job: "job", Start: start, End: start, Outcome: o, Attempt: 1}
+}
+
+func Test_History_RingBuffer(t *testing.T) {
+	h, err := newHistory(3, 0)
+
+	if err != nil {
+		t.Fatal("Failed to create history: ", err)
+	}
+
+	now := time.Now()
+	for i := 1; i <= 5; i++ {
+		h.add(record(i, Success, now.Add(time.Duration(i)*time.Second)))
+	}
+
+	rs := h.query(HistoryFilter{}, now)
+
+	if len(rs) != 3 {
+		t.Fatal("Failed to limit history size: ", len(rs))
+	}
+
+	if rs[0].RunID != "job-5" || rs[2].RunID != "job-3" {
+		t.Error("Failed to keep the newest records: ", rs[0].RunID, rs[2].RunID)
+	}
+}
+
+func Test_History_MaxAge(t *testing.T) {
+	h, err := newHistory(10, time.Minute)
+
+	if err != nil {
+		t.Fatal("Failed to create history: ", err)
+	}
+
+	now := time.Now()
+	h.add(record(1, Success, now.Add(-time.Hour)))
+	h.add(record(2, Success, now.Add(-time.Second)))
+
+	rs := h.query(HistoryFilter{}, now)
+
+	if len(rs) != 1 || rs[0].RunID != "job-2" {
+		t.Error("Failed to remove old records: ", rs)
+	}
+}
+
+func Test_History_CreateError(t *testing.T) {
+	if _, err := newHistory(0, 0); err == nil {
+		t.Error("Failed to detect zero history size")
+	}
+
+	if _, err := newHistory(1, -time.Second); err == nil {
+		t.Error("Failed to detect negative max age")
+	}
+}
+
+func Test_History_Resize(t *testing.T) {
+	h, _ := newHistory(5, 0)
+
+	now := time.Now()
+	for i := 1; i <= 5; i++ {
+		h.add(record(i, Success, now))
+	}
+
+	if err := h.resize(2, 0); err != nil {
+		t.Fatal("Failed to resize history: ", err)
+	}
+
+	rs := h.query(HistoryFilter{}, now)
+
+	if len(rs) != 2 || rs[0].RunID != "job-5" || rs[1].RunID != "job-4" {
+		t.Error("Failed to keep the newest records after resize: ", rs)
+	}
+}
+
+func Test_History_QueryFilter(t *testing.T) {
+	h, _ := newHistory(10, 0)
+
+	now := time.Now()
+	h.add(record(1, Success, now.Add(1*time.Second)))
+	h.add(record(2, Failure, now.Add(2*time.Second)))
+	h.add(record(3, Success, now.Add(3*time.Second)))
+	h.add(record(4, Failure, now.Add(4*time.Second)))
+	h.add(record(5, Success, now.Add(5*time.Second)))
+
+	rs := h.query(HistoryFilter{Outcome: Failure}, now)
+
+	if len(rs) != 2 || rs[0].RunID != "job-4" || rs[1].RunID != "job-2" {
+		t.Error("Failed to filter by outcome: ", rs)
+	}
+
+	rs = h.query(HistoryFilter{From: now.Add(2 * time.Second), To: now.Add(4 * time.Second)}, now)
+
+	if len(rs) != 2 || rs[0].RunID != "job-3" || rs[1].RunID != "job-2" {
+		t.Error("Failed to filter by time range: ", rs)
+	}
+
+	rs = h.query(HistoryFilter{Offset: 1, Limit: 2}, now)
+
+	if len(rs) != 2 || rs[0].RunID != "job-4" || rs[1].RunID != "job-3" {
+		t.Error("Failed to page records: ", rs)
+	}
+}
+
+func Test_Worker_History(t *testing.T) {
+	worker := NewWorker()
+	name := "failing"
+	runs := 0
+
+	foo := func(ctx context.Context) error {
+		runs++
+		if runs%2 == 0 {
+			return fmt.Errorf("even run")
+		}
+
+		return nil
+	}
+
+	a, err := tk.Create(time.Millisecond*50, time.Second, 0, foo)
+
+	if err != nil {
+		t.Error("Failed to create task: ", err)
+	}
+
+	if err := worker.Add(a, name); err != nil {
+		t.Error("Failed to add task to worker: ", err)
+	}
+
+	if _, err := worker.LastRun(name, Success); err == nil {
+		t.Error("Failed to detect empty history")
+	}
+
+	if err := worker.Start(name); err != nil {
+		t.Error("Failed to start worker: ", err)
+	}
+
+	time.Sleep(time.Millisecond * 220)
+
+	if err := worker.Stop(name); err != nil {
+		t.Error("Failed to stop worker: ", err)
+	}
+
+	time.Sleep(time.Millisecond * 50)
+
+	rs, err := worker.History(name, HistoryFilter{})
+
+	if err != nil {
+		t.Fatal("Failed to get history: ", err)
+	}
+
+	if len(rs) < 3 {
+		t.Fatal("Failed to record runs: ", len(rs))
+	}
+
+	for _, r := range rs {
+		if r.Job != name || r.Start.Before(r.PlannedStart.Add(-time.Millisecond*10)) || r.End.Before(r.Start) || r.Attempt != 1 {
+			t.Error("Wrong run record: ", r)
+		}
+	}
+
+	last, err := worker.LastRun(name, Failure)
+
+	if err != nil {
+		t.Fatal("Failed to get last failed run: ", err)
+	}
+
+	if last.Err != "even run" {
+		t.Error("Failed to record run error: ", last.Err)
+	}
+
+	if _, err := worker.History(name+"1", HistoryFilter{}); err == nil {
+		t.Error("Failed to detect missing job")
+	}
+
+	if _, err := worker.History(name, HistoryFilter{Limit: -1}); err == nil {
+		t.Error("Failed to detect negative limit")
+	}
+}
+
+func Test_Worker_SetHistoryLimits(t *testing.T) {
+	worker := NewWorker()
+	name := "printing"
+
+	a, err := tk.Create(time.Second*3, time.Second*3, time.Second*1, outer("hello"))
+
+	if err != nil {
+		t.Error("Failed to create task: ", err)
+	}
+
+	if err := worker.Add(a, name); err != nil {
+		t.Error("Failed to add task to worker: ", err)
+	}
+
+	if err := worker.SetHistoryLimits(0, 0); err == nil {
+		t.Error("Failed to detect wrong history size")
+	}
+
+	if err := worker.SetHistoryLimits(5, time.Hour); err != nil {
+		t.Error("Failed to set history limits: ", err)
+	}
+
+	if len(worker.jobs[name].history.records) != 5 || worker.jobs[name].history.maxAge != time.Hour {
+		t.Error("Failed to apply history limits to job")
+	}
+
+	if err := worker.SetJobHistoryLimits(name, 2, 0); err != nil {
+		t.Error("Failed to set job history limits: ", err)
+	}
+
+	if len(worker.jobs[name].history.records) != 2 {
+		t.Error("Failed to apply job history limits")
+	}
+
+	if err := worker.SetJobHistoryLimits(name+"1", 2, 0); err == nil {
+		t.Error("Failed to detect missing job")
+	}
+}
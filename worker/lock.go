@@ -0,0 +1,138 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"time"
+
+	"github.com/vslchnk/goscheduler/lock"
+	t "github.com/vslchnk/goscheduler/task"
+)
+
+// sets locker consulted before every run of job, run is skipped if lease of job's name is held by another replica;
+// lease is taken for ttl and renewed every third of it while task runs and till half way to the next run, losing lease cancels run;
+// run is recorded as failed to lock if locker fails; nil locker disables locking
+func (w *worker) SetLocker(l lock.Locker, ttl time.Duration) error {
+	if l != nil && ttl <= 0 {
+		return fmt.Errorf("TTL must be greater than 0")
+	}
+
+	defer w.Unlock()
+	w.Lock()
+
+	w.locker = l
+	w.lockTTL = ttl
+
+	return nil
+}
+
+// sets leader of worker, jobs' runs are skipped while replica isn't leader; leader must be run by caller, nil leader disables leadership
+func (w *worker) SetLeader(ld *lock.Leader) {
+	defer w.Unlock()
+	w.Lock()
+
+	w.leader = ld
+}
+
+// error of run skipped while replica isn't leader
+var errNotLeader = errors.New("Replica isn't leader")
+
+// characters of job's name kept in key of its lease
+var keyName = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// max length of job's name kept in key of its lease
+const maxKeyName = 128
+
+// returns key of lease of job n valid for any locker: name with unsafe characters replaced and its hash if it's changed
+func lockKey(n string) string {
+	k := keyName.ReplaceAllString(n, "_")
+	if len(k) > maxKeyName {
+		k = k[:maxKeyName]
+	}
+
+	if k == n && k != "." && k != ".." {
+		return k
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(n))
+
+	return fmt.Sprintf("%v-%016x", k, h.Sum64())
+}
+
+// acquires leases of run of job n, returns context of run with fencing token and func releasing leases;
+// lease of job is kept until hold after run ends, or for its ttl if hold is zero, so replicas firing the same run later skip it.
+// returns ErrLocked if another replica holds lease of job and errNotLeader if replica isn't leader
+func (w *worker) lease(n string, hold time.Time) (context.Context, func(), error) {
+	w.Lock()
+	locker, ttl, leader := w.locker, w.lockTTL, w.leader
+	w.Unlock()
+
+	ctx := context.Background()
+
+	if leader != nil {
+		l, ok := leader.Lease()
+		if !ok {
+			return nil, nil, errNotLeader
+		}
+
+		ctx = t.WithFencingToken(ctx, l.Token)
+	}
+
+	if locker == nil {
+		return ctx, func() {}, nil
+	}
+
+	l, err := locker.Acquire(ctx, lockKey(n), ttl)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if hold.IsZero() {
+		hold = time.Now().Add(ttl)
+	}
+
+	ctx, cancel := context.WithCancel(t.WithFencingToken(ctx, l.Token))
+	finished := make(chan struct{})
+
+	go func() {
+		defer locker.Release(context.Background(), l)
+
+		ticker := time.NewTicker(lock.RenewInterval(ttl))
+		defer ticker.Stop()
+
+		var held <-chan time.Time
+		for {
+			select {
+			case <-finished:
+				finished = nil
+				held = time.After(time.Until(hold))
+			case <-held:
+				return
+			case <-ticker.C:
+				if l, err = locker.Renew(context.Background(), l, ttl); err != nil {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	return ctx, func() {
+		cancel()
+		close(finished)
+	}, nil
+}
+
+// records run s of job skipped because its lease can't be acquired
+func (w *worker) unleased(n string, j *job, s slot, err error) {
+	err = fmt.Errorf("Failed to acquire lease of run: %v", err)
+	rec := j.begin(n, s, "")
+	j.Lock()
+	rec = j.end(rec, LockFailed, err)
+	j.Unlock()
+	w.finished(n, rec, err)
+}
@@ -0,0 +1,276 @@
+//go:build unix
+
+package worker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vslchnk/goscheduler/lock"
+	tk "github.com/vslchnk/goscheduler/task"
+)
+
+func Test_Worker_Locker(t *testing.T) {
+	dir := t.TempDir()
+	name := "locked"
+
+	mu := sync.Mutex{}
+	running := 0
+	overlaps := 0
+	tokens := []int64{}
+
+	do := func(ctx context.Context) error {
+		token, ok := tk.FencingToken(ctx)
+
+		mu.Lock()
+		running++
+		if running > 1 || !ok {
+			overlaps++
+		}
+		tokens = append(tokens, token)
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond * 15)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+
+		return nil
+	}
+
+	workers := []*worker{}
+	for _, owner := range []string{"a", "b"} {
+		l, err := lock.NewFileLocker(dir, owner)
+		if err != nil {
+			t.Fatal("Failed to create locker: ", err)
+		}
+
+		a, err := tk.Create(time.Millisecond*10, time.Second, 0, do)
+		if err != nil {
+			t.Fatal("Failed to create task: ", err)
+		}
+
+		w := NewWorker()
+		if err := w.SetLocker(l, time.Second); err != nil {
+			t.Fatal("Failed to set locker: ", err)
+		}
+
+		if err := w.Add(a, name); err != nil {
+			t.Fatal("Failed to add task to worker: ", err)
+		}
+
+		workers = append(workers, w)
+	}
+
+	for _, w := range workers {
+		if err := w.Start(name); err != nil {
+			t.Error("Failed to start worker: ", err)
+		}
+	}
+
+	time.Sleep(time.Millisecond * 200)
+
+	for _, w := range workers {
+		w.Kill(name)
+	}
+
+	time.Sleep(time.Millisecond * 50)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if overlaps > 0 || len(tokens) < 3 {
+		t.Error("Failed to run job by one replica at a time: ", overlaps, tokens)
+	}
+
+	for i := 1; i < len(tokens); i++ {
+		if tokens[i] <= tokens[i-1] {
+			t.Error("Fencing tokens don't grow: ", tokens)
+		}
+	}
+
+	if err := workers[0].SetLocker(workers[0].locker, 0); err == nil {
+		t.Error("Failed to detect wrong ttl")
+	}
+}
+
+func Test_Worker_Leader(t *testing.T) {
+	l, err := lock.NewFileLocker(t.TempDir(), "a")
+	if err != nil {
+		t.Fatal("Failed to create locker: ", err)
+	}
+
+	ld, err := lock.NewLeader(l, "leader", time.Millisecond*30)
+	if err != nil {
+		t.Fatal("Failed to create leader: ", err)
+	}
+
+	worker := NewWorker()
+	worker.SetLeader(ld)
+	name := "led"
+	runs := make(chan int64, 100)
+
+	a, err := tk.Create(time.Millisecond*10, time.Second, 0, func(ctx context.Context) error {
+		token, _ := tk.FencingToken(ctx)
+		runs <- token
+
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	if err := worker.Add(a, name); err != nil {
+		t.Fatal("Failed to add task to worker: ", err)
+	}
+
+	if err := worker.Start(name); err != nil {
+		t.Error("Failed to start worker: ", err)
+	}
+	defer worker.Kill(name)
+
+	time.Sleep(time.Millisecond * 50)
+
+	if len(runs) != 0 {
+		t.Error("Job runs while replica isn't leader: ", len(runs))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go ld.Run(ctx)
+
+	time.Sleep(time.Millisecond * 50)
+
+	if len(runs) == 0 {
+		t.Fatal("Job doesn't run while replica is leader")
+	}
+
+	if token := <-runs; token != 1 {
+		t.Error("Wrong fencing token of leader: ", token)
+	}
+}
+
+func Test_Worker_LockerTicks(t *testing.T) {
+	dir := t.TempDir()
+	name := "reports/daily"
+
+	mu := sync.Mutex{}
+	ticks := map[int64]int{}
+
+	do := func(ctx context.Context) error {
+		mu.Lock()
+		ticks[time.Now().Round(time.Millisecond*100).UnixNano()]++
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		return nil
+	}
+
+	workers := []*worker{}
+	for _, owner := range []string{"a", "b"} {
+		l, err := lock.NewFileLocker(dir, owner)
+		if err != nil {
+			t.Fatal("Failed to create locker: ", err)
+		}
+
+		a, err := tk.Create(time.Millisecond*100, time.Second, 0, do)
+		if err != nil {
+			t.Fatal("Failed to create task: ", err)
+		}
+
+		a.SetAlignment(time.Millisecond * 100)
+		a.SetJitter(time.Millisecond * 20)
+
+		w := NewWorker()
+		w.SetLocker(l, time.Second)
+		w.Add(a, name)
+		workers = append(workers, w)
+	}
+
+	for _, w := range workers {
+		if err := w.Start(name); err != nil {
+			t.Error("Failed to start worker: ", err)
+		}
+	}
+
+	time.Sleep(time.Millisecond * 1050)
+
+	for _, w := range workers {
+		w.Kill(name)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(ticks) < 8 {
+		t.Error("Wrong number of ticks: ", len(ticks))
+	}
+
+	for tick, runs := range ticks {
+		if runs > 1 {
+			t.Error("Tick is run by several replicas: ", time.Unix(0, tick), runs)
+		}
+	}
+}
+
+// locker failing to acquire leases
+type failingLocker struct {
+	lock.Locker
+}
+
+func (failingLocker) Acquire(ctx context.Context, key string, ttl time.Duration) (lock.Lease, error) {
+	return lock.Lease{}, errors.New("Database is down")
+}
+
+func Test_Worker_LockerError(t *testing.T) {
+	if k := lockKey("reports"); k != "reports" {
+		t.Error("Failed to keep valid name in key: ", k)
+	}
+
+	if k := lockKey("reports/daily"); !strings.HasPrefix(k, "reports_daily-") || k == lockKey("reports_daily") {
+		t.Error("Failed to derive key from name: ", k)
+	}
+
+	if k := lockKey(".."); k == ".." {
+		t.Error("Failed to derive key from name: ", k)
+	}
+
+	worker := NewWorker()
+	worker.SetLocker(failingLocker{}, time.Second)
+	name := "unlocked"
+
+	a, err := tk.Create(time.Millisecond*10, time.Second, 0, func(ctx context.Context) error {
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	worker.Add(a, name)
+
+	if err := worker.Start(name); err != nil {
+		t.Error("Failed to start worker: ", err)
+	}
+
+	time.Sleep(time.Millisecond * 35)
+	worker.Kill(name)
+
+	records, _ := worker.History(name, HistoryFilter{})
+	if len(records) == 0 {
+		t.Fatal("Failed to record runs skipped by locker")
+	}
+
+	for _, r := range records {
+		if r.Outcome != LockFailed || !strings.Contains(r.Err, "Database is down") {
+			t.Error("Wrong record of run skipped by locker: ", r.Outcome, r.Err)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vslchnk/goscheduler/lock"
+	"github.com/vslchnk/goscheduler/queue"
+	t "github.com/vslchnk/goscheduler/task"
+)
+
+// sets queue of worker, runs of started jobs are put to queue instead of being executed and are executed by consumers; nil queue disables queue mode
+func (w *worker) SetQueue(q queue.Queue) {
+	defer w.Unlock()
+	w.Lock()
+
+	w.queue = q
+}
+
+// puts run s of job to worker's queue and records it in job's history, returns false if worker has no queue
+func (w *worker) enqueue(ctx context.Context, n string, j *job, task t.Task, s slot) bool {
+	w.Lock()
+	q := w.queue
+	w.Unlock()
+
+	if q == nil {
+		return false
+	}
+
+	rec := j.begin(n, s, "")
+	payload, err := task.GetPayloadJSON()
+	if errors.Is(err, t.ErrNoPayload) {
+		payload, err = nil, nil
+	}
+
+	if err == nil {
+		err = q.Enqueue(ctx, queue.Message{Job: n, RunID: rec.RunID, Planned: s.planned, Payload: payload})
+	}
+
+	j.Lock()
+	j.transition(wtf)
+	if err != nil {
+		rec = j.end(rec, Failure, err)
+	} else {
+		rec = j.end(rec, Enqueued, nil)
+	}
+	j.Unlock()
+
+	w.emit(Event{Type: EventRunFinished, Job: n, Time: rec.End, Run: rec, Message: rec.Err})
+
+	return true
+}
+
+// receives runs from q and executes tasks of their jobs until ctx is done, jobs must be added to worker, but needn't be started;
+// received run is hidden from other consumers for visibility which is extended every third of it while run lasts,
+// run is cancelled if it's delivered again meanwhile; it's acknowledged if it succeeds and returned to queue after poll if it fails,
+// q is polled every poll while it's empty or fails; several consumers may run concurrently
+func (w *worker) Consume(ctx context.Context, q queue.Queue, visibility time.Duration, poll time.Duration) error {
+	if q == nil {
+		return fmt.Errorf("No queue provided")
+	}
+
+	if visibility <= 0 || poll <= 0 {
+		return fmt.Errorf("Visibility timeout and poll interval must be greater than 0")
+	}
+
+	for ctx.Err() == nil {
+		m, err := q.Receive(ctx, visibility)
+		if err == nil {
+			w.consume(ctx, q, m, visibility, poll)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(poll):
+		}
+	}
+
+	return nil
+}
+
+// executes run received from q with payload of message keeping it hidden for visibility, run's parent is run which enqueued it
+func (w *worker) consume(ctx context.Context, q queue.Queue, m queue.Message, visibility time.Duration, retry time.Duration) {
+	j, err := w.get(m.Job)
+	if err != nil {
+		q.Nack(ctx, m, retry)
+		return
+	}
+
+	j.Lock()
+	task := j.task
+	j.Unlock()
+
+	if m.Payload != nil {
+		if err := task.SetPayloadJSON(m.Payload); err != nil {
+			q.Nack(ctx, m, retry)
+			return
+		}
+	}
+
+	run, cancel := context.WithCancel(ctx)
+	stop := extend(run, cancel, q, m, visibility)
+	rec := w.execute(run, m.Job, j, task, nil, m.RunID)
+	stop()
+
+	if rec.Outcome == Success {
+		q.Ack(ctx, m)
+	} else {
+		q.Nack(ctx, m, retry)
+	}
+}
+
+// extends visibility of message m every third of it until returned func is called, cancels run if m is delivered again
+func extend(ctx context.Context, cancel context.CancelFunc, q queue.Queue, m queue.Message, visibility time.Duration) func() {
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(lock.RenewInterval(visibility))
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := q.Extend(ctx, m, visibility); errors.Is(err, queue.ErrStaleReceipt) {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-stopped
+		cancel()
+	}
+}
@@ -0,0 +1,216 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vslchnk/goscheduler/queue"
+	tk "github.com/vslchnk/goscheduler/task"
+)
+
+func Test_Worker_Queue(t *testing.T) {
+	q, err := queue.NewMemory(2)
+	if err != nil {
+		t.Fatal("Failed to create queue: ", err)
+	}
+
+	mu := sync.Mutex{}
+	got := []string{}
+
+	typed := func(value string) tk.Task {
+		a, err := tk.CreateTyped(time.Millisecond*20, time.Second, 0, value, func(ctx context.Context, s string) error {
+			mu.Lock()
+			got = append(got, s)
+			mu.Unlock()
+
+			return nil
+		})
+
+		if err != nil {
+			t.Fatal("Failed to create task: ", err)
+		}
+
+		return a.Task
+	}
+
+	failing, err := tk.Create(time.Millisecond*20, time.Second, 0, func(ctx context.Context) error {
+		return fmt.Errorf("broken")
+	})
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	scheduler := NewWorker()
+	scheduler.SetQueue(q)
+
+	if err := scheduler.Add(typed("scheduled"), "typed"); err != nil {
+		t.Fatal("Failed to add task to worker: ", err)
+	}
+
+	if err := scheduler.Add(failing, "failing"); err != nil {
+		t.Fatal("Failed to add task to worker: ", err)
+	}
+
+	if err := scheduler.StartAll(); err != nil {
+		t.Fatal("Failed to start worker: ", err)
+	}
+
+	time.Sleep(time.Millisecond * 50)
+
+	if err := scheduler.StopAll(); err != nil {
+		t.Error("Failed to stop worker: ", err)
+	}
+
+	if runs, _ := scheduler.History("typed", HistoryFilter{}); len(runs) == 0 || runs[0].Outcome != Enqueued {
+		t.Error("Failed to record enqueued runs: ", runs)
+	}
+
+	mu.Lock()
+	if len(got) != 0 {
+		t.Error("Scheduler executed task in queue mode: ", got)
+	}
+	mu.Unlock()
+
+	consumer := NewWorker()
+	consumer.Add(typed("consumer"), "typed")
+	consumer.Add(failing, "failing")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			done <- consumer.Consume(ctx, q, time.Second, time.Millisecond*5)
+		}()
+	}
+
+	time.Sleep(time.Millisecond * 100)
+	cancel()
+
+	for i := 0; i < 2; i++ {
+		if err := <-done; err != nil {
+			t.Error("Failed to consume queue: ", err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(got) == 0 || got[0] != "scheduled" {
+		t.Error("Failed to execute enqueued runs: ", got)
+	}
+
+	runs, _ := consumer.History("typed", HistoryFilter{})
+	if len(runs) != len(got) || runs[0].Outcome != Success || runs[0].ParentRunID == "" {
+		t.Error("Wrong history of consumed runs: ", runs)
+	}
+
+	dead, _ := q.DeadLetters(context.Background())
+	if len(dead) == 0 || dead[0].Job != "failing" || dead[0].Attempts != 2 {
+		t.Error("Failed to dead-letter failing runs: ", dead)
+	}
+
+	if _, err := q.Receive(context.Background(), time.Second); err == nil {
+		t.Error("Messages left in queue")
+	}
+
+	if err := consumer.Consume(ctx, nil, time.Second, time.Second); err == nil {
+		t.Error("Failed to detect missing queue")
+	}
+}
+
+// payload which can be encoded to JSON only once
+type brokenPayload struct {
+	encoded *int32
+}
+
+func (p brokenPayload) MarshalJSON() ([]byte, error) {
+	if atomic.AddInt32(p.encoded, 1) > 1 {
+		return nil, fmt.Errorf("Payload is broken")
+	}
+
+	return []byte("{}"), nil
+}
+
+func Test_Worker_QueuePayloadError(t *testing.T) {
+	q, _ := queue.NewMemory(1)
+
+	a, err := tk.CreateTyped(time.Millisecond*20, time.Second, 0, brokenPayload{encoded: new(int32)}, func(ctx context.Context, p brokenPayload) error {
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	worker := NewWorker()
+	worker.SetQueue(q)
+	worker.Add(a.Task, "broken")
+
+	if err := worker.Start("broken"); err != nil {
+		t.Fatal("Failed to start worker: ", err)
+	}
+
+	time.Sleep(time.Millisecond * 30)
+	worker.Kill("broken")
+
+	runs, _ := worker.History("broken", HistoryFilter{})
+	if len(runs) == 0 || runs[0].Outcome != Failure || !strings.Contains(runs[0].Err, "Payload is broken") {
+		t.Error("Failed to record run with broken payload: ", runs)
+	}
+
+	if _, err := q.Receive(context.Background(), time.Second); err == nil {
+		t.Error("Run with broken payload is enqueued")
+	}
+}
+
+func Test_Worker_QueueVisibility(t *testing.T) {
+	q, err := queue.NewMemory(3)
+	if err != nil {
+		t.Fatal("Failed to create queue: ", err)
+	}
+
+	var runs atomic.Int32
+	a, err := tk.Create(time.Second, time.Second, 0, func(ctx context.Context) error {
+		runs.Add(1)
+		time.Sleep(time.Millisecond * 100)
+
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	consumer := NewWorker()
+	consumer.Add(a, "slow")
+	q.Enqueue(context.Background(), queue.Message{Job: "slow", RunID: "slow-1"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			done <- consumer.Consume(ctx, q, time.Millisecond*30, time.Millisecond*5)
+		}()
+	}
+
+	time.Sleep(time.Millisecond * 200)
+	cancel()
+
+	for i := 0; i < 2; i++ {
+		<-done
+	}
+
+	if n := runs.Load(); n != 1 {
+		t.Error("Run longer than visibility timeout is delivered again: ", n)
+	}
+
+	if records, _ := consumer.History("slow", HistoryFilter{}); len(records) != 1 || records[0].Outcome != Success {
+		t.Error("Wrong history of consumed run: ", records)
+	}
+}
@@ -0,0 +1,123 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vslchnk/goscheduler/ratelimit"
+	t "github.com/vslchnk/goscheduler/task"
+)
+
+// sets rate limit of group of tasks: runs of all jobs whose tasks belong to group share token bucket holding up to burst tokens
+// and refilled every interval; limit replaces old limit of group
+func (w *worker) SetRateGroup(group string, every time.Duration, burst int) error {
+	if group == "" {
+		return fmt.Errorf("No group provided")
+	}
+
+	b, err := ratelimit.NewBucket(every, burst)
+	if err != nil {
+		return err
+	}
+
+	defer w.Unlock()
+	w.Lock()
+
+	w.rateGroups[group] = b
+
+	return nil
+}
+
+// returns token bucket of task's rate limit, nil if task isn't limited
+func jobBucket(task t.Task) (*ratelimit.Bucket, error) {
+	every, burst := task.GetRateLimit()
+	if every == 0 {
+		return nil, nil
+	}
+
+	return ratelimit.NewBucket(every, burst)
+}
+
+// checks if rate limit group of task is set on worker, worker must be locked
+func (w *worker) checkGroup(task t.Task) error {
+	if g := task.GetRateGroup(); g != "" && w.rateGroups[g] == nil {
+		return fmt.Errorf("Rate group %q not found", g)
+	}
+
+	return nil
+}
+
+// applies rate limits of job and its group to run s, run exceeding them is delayed till they allow it or skipped if task skips it;
+// returns false if stop is done while run is delayed, tokens taken by run are returned then
+func (w *worker) throttle(n string, j *job, stop context.Context, task t.Task, s slot) (slot, bool) {
+	names := []string{}
+	buckets := []*ratelimit.Bucket{}
+
+	j.Lock()
+	if j.bucket == nil {
+		// job which isn't started gets bucket on its first run, e.g. run triggered by chain, workflow or queue
+		j.bucket, _ = jobBucket(task)
+	}
+	if j.bucket != nil {
+		names, buckets = append(names, "job"), append(buckets, j.bucket)
+	}
+	j.Unlock()
+
+	w.Lock()
+	if g := task.GetRateGroup(); g != "" && w.rateGroups[g] != nil {
+		names, buckets = append(names, fmt.Sprintf("group %v", g)), append(buckets, w.rateGroups[g])
+	}
+	w.Unlock()
+
+	now := time.Now()
+
+	if task.GetRateAction() == t.RateSkip {
+		for i, b := range buckets {
+			if !b.Allow(now) {
+				for _, taken := range buckets[:i] {
+					taken.Return()
+				}
+
+				s.skip, s.outcome, s.reason = true, Throttled, fmt.Sprintf("Rate limit of %v is exceeded", names[i])
+
+				return s, true
+			}
+		}
+
+		return s, true
+	}
+
+	wait, name := time.Duration(0), ""
+	for i, b := range buckets {
+		if d := b.Reserve(now); d > wait {
+			wait, name = d, names[i]
+		}
+	}
+
+	if wait == 0 {
+		return s, true
+	}
+
+	reason := fmt.Sprintf("Delayed for %v by rate limit of %v", wait, name)
+	if s.reason != "" {
+		reason = s.reason + "; " + reason
+	}
+	s.reason = reason
+
+	timer := time.NewTimer(wait)
+	select {
+	case <-stop.Done():
+		timer.Stop()
+
+		// run doesn't happen, so its tokens don't delay other runs
+		for _, b := range buckets {
+			b.Return()
+		}
+
+		return s, false
+	case <-timer.C:
+	}
+
+	return s, true
+}
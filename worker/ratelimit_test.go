@@ -0,0 +1,187 @@
+package worker
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tk "github.com/vslchnk/goscheduler/task"
+)
+
+func Test_RateLimit_Delay(t *testing.T) {
+	worker := NewWorker()
+	name := "limited"
+
+	a, err := tk.Create(time.Millisecond*10, time.Second, 0, func(ctx context.Context) error {
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	a.SetRateLimit(time.Millisecond*50, 1)
+	worker.Add(a, name)
+
+	if err := worker.Start(name); err != nil {
+		t.Error("Failed to start worker: ", err)
+	}
+
+	time.Sleep(time.Millisecond * 230)
+	worker.Stop(name)
+
+	records, _ := worker.History(name, HistoryFilter{})
+	if len(records) < 3 || len(records) > 6 {
+		t.Fatal("Failed to limit rate of runs: ", len(records))
+	}
+
+	delayed := 0
+	for _, r := range records {
+		if r.Outcome != Success {
+			t.Error("Wrong outcome of delayed run: ", r.Outcome)
+		}
+
+		if strings.Contains(r.Reason, "rate limit of job") {
+			delayed++
+			if r.Lateness < time.Millisecond*20 {
+				t.Error("Failed to record lateness of delayed run: ", r.Lateness)
+			}
+		}
+	}
+
+	if delayed == 0 {
+		t.Error("Failed to delay runs")
+	}
+}
+
+func Test_RateLimit_Group(t *testing.T) {
+	worker := NewWorker()
+
+	if err := worker.SetRateGroup("", time.Second, 1); err == nil {
+		t.Error("Failed to detect missing group")
+	}
+
+	if err := worker.SetRateGroup("api", 0, 1); err == nil {
+		t.Error("Failed to detect wrong interval")
+	}
+
+	for _, name := range []string{"first", "second"} {
+		a, err := tk.Create(time.Millisecond*10, time.Second, 0, func(ctx context.Context) error {
+			return nil
+		})
+
+		if err != nil {
+			t.Fatal("Failed to create task: ", err)
+		}
+
+		a.SetRateGroup("api")
+		a.SetRateAction(tk.RateSkip)
+		worker.Add(a, name)
+	}
+
+	if err := worker.Start("first"); err == nil {
+		t.Error("Failed to detect unknown group")
+	}
+
+	if err := worker.SetRateGroup("api", time.Millisecond*100, 2); err != nil {
+		t.Fatal("Failed to set rate group: ", err)
+	}
+
+	for _, name := range []string{"first", "second"} {
+		if err := worker.Start(name); err != nil {
+			t.Error("Failed to start worker: ", err)
+		}
+	}
+
+	time.Sleep(time.Millisecond * 250)
+	worker.StopAll()
+
+	succeeded, throttled := 0, 0
+	for _, name := range []string{"first", "second"} {
+		records, _ := worker.History(name, HistoryFilter{})
+		for _, r := range records {
+			switch r.Outcome {
+			case Success:
+				succeeded++
+			case Throttled:
+				throttled++
+				if !strings.Contains(r.Reason, "group api") {
+					t.Error("Wrong reason of throttled run: ", r.Reason)
+				}
+			}
+		}
+	}
+
+	if succeeded < 2 || succeeded > 6 || throttled == 0 {
+		t.Error("Failed to share rate limit of group: ", succeeded, throttled)
+	}
+}
+
+func Test_RateLimit_StopReturnsTokens(t *testing.T) {
+	worker := NewWorker()
+	name := "delayed"
+
+	if err := worker.SetRateGroup("api", time.Millisecond*200, 1); err != nil {
+		t.Fatal("Failed to set rate group: ", err)
+	}
+
+	a, err := tk.Create(time.Millisecond*10, time.Second, 0, func(ctx context.Context) error {
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	a.SetRateGroup("api")
+	worker.Add(a, name)
+
+	if err := worker.Start(name); err != nil {
+		t.Error("Failed to start worker: ", err)
+	}
+
+	// the second run is delayed by group's limit when job is stopped
+	time.Sleep(time.Millisecond * 50)
+	worker.Stop(name)
+	time.Sleep(time.Millisecond * 10)
+
+	worker.Lock()
+	group := worker.rateGroups["api"]
+	worker.Unlock()
+
+	if wait := group.Reserve(time.Now()); wait > time.Millisecond*190 {
+		t.Error("Failed to return tokens of run which isn't done: ", wait)
+	}
+}
+
+func Test_RateLimit_RunOnce(t *testing.T) {
+	worker := NewWorker()
+	name := "triggered"
+
+	a, err := tk.Create(time.Hour, time.Second, 0, func(ctx context.Context) error {
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	a.SetRateLimit(time.Minute, 1)
+	a.SetRateAction(tk.RateSkip)
+	worker.Add(a, name)
+
+	outcomes := []Outcome{}
+	for i := 0; i < 3; i++ {
+		rec, err := worker.runOnce(name, nil, "")
+		if err != nil {
+			t.Fatal("Failed to run job: ", err)
+		}
+
+		outcomes = append(outcomes, rec.Outcome)
+	}
+
+	if outcomes[0] != Success || outcomes[1] != Throttled || outcomes[2] != Throttled {
+		t.Error("Failed to limit rate of runs outside of schedule: ", outcomes)
+	}
+}
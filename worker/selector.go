@@ -0,0 +1,246 @@
+package worker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type operator string
+
+const (
+	equals    operator = "="
+	notEquals operator = "!="
+	exists    operator = "exists"
+	notExists operator = "!exists"
+)
+
+// requirement of selector to label of job
+type requirement struct {
+	key   string
+	op    operator
+	value string
+}
+
+// selects jobs by their labels, every requirement must match
+type Selector struct {
+	reqs []requirement
+}
+
+// error of bulk operation, holds error of every job which failed
+type BulkError struct {
+	Op     string
+	Errors map[string]error
+}
+
+func (e *BulkError) Error() string {
+	names := make([]string, 0, len(e.Errors))
+	for n := range e.Errors {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	msgs := make([]string, 0, len(names))
+	for _, n := range names {
+		msgs = append(msgs, fmt.Sprintf("%v: %v", n, e.Errors[n]))
+	}
+
+	return fmt.Sprintf("Error in %v(): %d jobs failed: %v", e.Op, len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// returns errors of all failed jobs
+func (e *BulkError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// parses selector like "team=billing,env!=prod,critical,!deprecated", empty selector selects every job
+func ParseSelector(s string) (Selector, error) {
+	sel := Selector{}
+
+	if strings.TrimSpace(s) == "" {
+		return sel, nil
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		r := requirement{}
+
+		switch {
+		case strings.Contains(part, "!="):
+			kv := strings.SplitN(part, "!=", 2)
+			r = requirement{key: strings.TrimSpace(kv[0]), op: notEquals, value: strings.TrimSpace(kv[1])}
+		case strings.Contains(part, "=="):
+			kv := strings.SplitN(part, "==", 2)
+			r = requirement{key: strings.TrimSpace(kv[0]), op: equals, value: strings.TrimSpace(kv[1])}
+		case strings.Contains(part, "="):
+			kv := strings.SplitN(part, "=", 2)
+			r = requirement{key: strings.TrimSpace(kv[0]), op: equals, value: strings.TrimSpace(kv[1])}
+		case strings.HasPrefix(part, "!"):
+			r = requirement{key: strings.TrimSpace(part[1:]), op: notExists}
+		default:
+			r = requirement{key: part, op: exists}
+		}
+
+		if err := validLabel(r.key, r.value); err != nil {
+			return sel, fmt.Errorf("Selector %q is not valid: %v", s, err)
+		}
+
+		sel.reqs = append(sel.reqs, r)
+	}
+
+	return sel, nil
+}
+
+// checks if labels match selector
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, r := range s.reqs {
+		v, ok := labels[r.key]
+
+		switch r.op {
+		case equals:
+			if !ok || v != r.value {
+				return false
+			}
+		case notEquals:
+			if ok && v == r.value {
+				return false
+			}
+		case exists:
+			if !ok {
+				return false
+			}
+		case notExists:
+			if ok {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// checks if label key and value are valid
+func validLabel(key string, value string) error {
+	if key == "" {
+		return fmt.Errorf("Label key is empty")
+	}
+
+	if strings.ContainsAny(key, ",=! ") || strings.ContainsAny(value, ",=!") {
+		return fmt.Errorf("Label %v=%v contains one of forbidden symbols ',=!'", key, value)
+	}
+
+	return nil
+}
+
+// sets labels of job by its name, replacing old ones
+func (w *worker) SetLabels(n string, labels map[string]string) error {
+	for k, v := range labels {
+		if err := validLabel(k, v); err != nil {
+			return err
+		}
+	}
+
+	j, err := w.get(n)
+	if err != nil {
+		return err
+	}
+
+	defer j.Unlock()
+	j.Lock()
+
+	j.labels = make(map[string]string, len(labels))
+	for k, v := range labels {
+		j.labels[k] = v
+	}
+
+	return nil
+}
+
+// returns labels of job by its name
+func (w *worker) Labels(n string) (map[string]string, error) {
+	j, err := w.get(n)
+	if err != nil {
+		return nil, err
+	}
+
+	defer j.Unlock()
+	j.Lock()
+
+	labels := make(map[string]string, len(j.labels))
+	for k, v := range j.labels {
+		labels[k] = v
+	}
+
+	return labels, nil
+}
+
+// returns sorted names of jobs matching selector
+func (w *worker) Select(selector string) ([]string, error) {
+	sel, err := ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	defer w.Unlock()
+	w.Lock()
+
+	names := []string{}
+	for n, j := range w.jobs {
+		j.Lock()
+		ok := sel.Matches(j.labels)
+		j.Unlock()
+
+		if ok {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// applies op to every job matching selector, returns BulkError with errors of all failed jobs
+func (w *worker) bulk(name string, selector string, op func(n string) error) error {
+	names, err := w.Select(selector)
+	if err != nil {
+		return fmt.Errorf("Error in %v(): %v", name, err)
+	}
+
+	e := &BulkError{Op: name, Errors: make(map[string]error)}
+	for _, n := range names {
+		if err := op(n); err != nil {
+			e.Errors[n] = err
+		}
+	}
+
+	if len(e.Errors) > 0 {
+		return e
+	}
+
+	return nil
+}
+
+// starts every job matching selector
+func (w *worker) StartWhere(selector string) error {
+	return w.bulk("StartWhere", selector, w.Start)
+}
+
+// stops every job matching selector
+func (w *worker) StopWhere(selector string) error {
+	return w.bulk("StopWhere", selector, w.Stop)
+}
+
+// kills every job matching selector
+func (w *worker) KillWhere(selector string) error {
+	return w.bulk("KillWhere", selector, w.Kill)
+}
+
+// deletes every job matching selector
+func (w *worker) DeleteWhere(selector string) error {
+	return w.bulk("DeleteWhere", selector, w.Delete)
+}
@@ -0,0 +1,182 @@
+package worker
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tk "github.com/vslchnk/goscheduler/task"
+)
+
+func labeled(t *testing.T, jobs map[string]map[string]string) *worker {
+	worker := NewWorker()
+
+	for n, labels := range jobs {
+		a, err := tk.Create(time.Second*5, time.Second*5, time.Second*5, outer("hello"))
+
+		if err != nil {
+			t.Fatal("Failed to create task: ", err)
+		}
+
+		if err := worker.Add(a, n); err != nil {
+			t.Fatal("Failed to add task to worker: ", err)
+		}
+
+		if err := worker.SetLabels(n, labels); err != nil {
+			t.Fatal("Failed to set labels: ", err)
+		}
+	}
+
+	return worker
+}
+
+func Test_Selector_Parse(t *testing.T) {
+	labels := map[string]string{"team": "billing", "env": "prod", "critical": ""}
+
+	tests := []struct {
+		sel   string
+		match bool
+	}{
+		{"", true},
+		{"team=billing", true},
+		{"team==billing", true},
+		{"team=search", false},
+		{"team=billing,env!=prod", false},
+		{"team=billing, env!=staging", true},
+		{"critical", true},
+		{"!critical", false},
+		{"deprecated", false},
+		{"!deprecated", true},
+	}
+
+	for _, tt := range tests {
+		sel, err := ParseSelector(tt.sel)
+
+		if err != nil {
+			t.Error("Failed to parse selector ", tt.sel, ": ", err)
+			continue
+		}
+
+		if sel.Matches(labels) != tt.match {
+			t.Error("Wrong match of selector ", tt.sel)
+		}
+	}
+
+	for _, s := range []string{"=billing", "team=billing,", "!", "te am=x", "team=a=b"} {
+		if _, err := ParseSelector(s); err == nil {
+			t.Error("Failed to detect wrong selector ", s)
+		}
+	}
+}
+
+func Test_Selector_SetLabels(t *testing.T) {
+	worker := labeled(t, map[string]map[string]string{"a": {"team": "billing"}})
+
+	if err := worker.SetLabels("b", nil); err == nil {
+		t.Error("Failed to detect missing job")
+	}
+
+	if err := worker.SetLabels("a", map[string]string{"te=am": "x"}); err == nil {
+		t.Error("Failed to detect wrong label")
+	}
+
+	labels, err := worker.Labels("a")
+
+	if err != nil || labels["team"] != "billing" {
+		t.Error("Wrong labels: ", labels, err)
+	}
+
+	labels["team"] = "search"
+
+	if l, _ := worker.Labels("a"); l["team"] != "billing" {
+		t.Error("Labels of job changed outside of worker")
+	}
+
+	if _, err := worker.Labels("b"); err == nil {
+		t.Error("Failed to detect missing job")
+	}
+}
+
+func Test_Selector_Select(t *testing.T) {
+	worker := labeled(t, map[string]map[string]string{
+		"invoices": {"team": "billing", "env": "prod"},
+		"refunds":  {"team": "billing", "env": "staging"},
+		"index":    {"team": "search", "env": "prod"},
+	})
+
+	names, err := worker.Select("team=billing")
+
+	if err != nil || strings.Join(names, ",") != "invoices,refunds" {
+		t.Error("Wrong selected jobs: ", names, err)
+	}
+
+	names, _ = worker.Select("env=prod")
+
+	if strings.Join(names, ",") != "index,invoices" {
+		t.Error("Wrong selected jobs: ", names)
+	}
+
+	if _, err := worker.Select("=x"); err == nil {
+		t.Error("Failed to detect wrong selector")
+	}
+}
+
+func Test_Selector_BulkOperations(t *testing.T) {
+	worker := labeled(t, map[string]map[string]string{
+		"invoices": {"team": "billing"},
+		"refunds":  {"team": "billing"},
+		"index":    {"team": "search"},
+	})
+
+	if err := worker.Start("refunds"); err != nil {
+		t.Fatal("Failed to start worker: ", err)
+	}
+
+	err := worker.StartWhere("team=billing")
+
+	var be *BulkError
+	if !errors.As(err, &be) || len(be.Errors) != 1 || be.Errors["refunds"] == nil || be.Op != "StartWhere" {
+		t.Fatal("Failed to aggregate errors: ", err)
+	}
+
+	if !strings.Contains(err.Error(), "refunds") {
+		t.Error("Error doesn't describe failed job: ", err)
+	}
+
+	worker.jobs["invoices"].Lock()
+	status := worker.jobs["invoices"].status
+	worker.jobs["invoices"].Unlock()
+
+	if status != wtnf {
+		t.Error("Failed to start every matching job: ", status)
+	}
+
+	if err := worker.StopWhere("team=billing"); err != nil {
+		t.Error("Failed to stop jobs: ", err)
+	}
+
+	if err := worker.StopWhere("team=billing"); err == nil {
+		t.Error("Failed to detect stopped jobs")
+	} else if errors.As(err, &be) && len(be.Errors) != 2 {
+		t.Error("Failed to try every job: ", err)
+	}
+
+	if err := worker.StopWhere("=x"); err == nil {
+		t.Error("Failed to detect wrong selector")
+	}
+
+	time.Sleep(time.Millisecond * 50)
+
+	if err := worker.DeleteWhere("team=billing"); err != nil {
+		t.Error("Failed to delete jobs: ", err)
+	}
+
+	if names, _ := worker.Select(""); len(names) != 1 || names[0] != "index" {
+		t.Error("Wrong jobs left: ", names)
+	}
+
+	if err := worker.KillWhere("team=search"); err == nil {
+		t.Error("Failed to detect job which isn't working")
+	}
+}
@@ -0,0 +1,80 @@
+package worker
+
+import (
+	"fmt"
+)
+
+// status of job in its lifecycle
+type Status string
+
+const (
+	cnw  Status = "created, not working"
+	wtnf Status = "working, task isn't finished"
+	wtf  Status = "working, task is finished"
+	stp  Status = "stopping, waiting for task to finish"
+	sus  Status = "suspended, circuit breaker is open"
+	sss  Status = "stopped by stop signal"
+	ste  Status = "stopped, time has expired"
+	k    Status = "killed"
+	cmp  Status = "completed, no more runs"
+)
+
+// statuses of job
+const (
+	StatusCreated   = cnw
+	StatusRunning   = wtnf
+	StatusIdle      = wtf
+	StatusStopping  = stp
+	StatusSuspended = sus
+	StatusStopped   = sss
+	StatusExpired   = ste
+	StatusKilled    = k
+	StatusCompleted = cmp
+)
+
+// allowed transitions between statuses of job
+var transitions = map[Status][]Status{
+	cnw:  {wtnf},
+	wtnf: {wtf, stp, ste, k, cmp},
+	wtf:  {wtnf, stp, k, cmp, sus},
+	stp:  {sss, k},
+	sus:  {wtf, stp, k},
+	sss:  {wtnf},
+	ste:  {wtnf},
+	cmp:  {wtnf},
+	k:    {},
+}
+
+// checks if job with status s is working: its task runs or waits for the next run
+func (s Status) working() bool {
+	return s == wtnf || s == wtf || s == stp || s == sus
+}
+
+// changes status of job to status to if transition is allowed, job must be locked
+func (j *job) transition(to Status) error {
+	if j.status == to {
+		return nil
+	}
+
+	for _, s := range transitions[j.status] {
+		if s == to {
+			j.status = to
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w from %q to %q", ErrInvalidTransition, j.status, to)
+}
+
+// returns status of job by its name
+func (w *worker) Status(n string) (Status, error) {
+	j, err := w.get(n)
+	if err != nil {
+		return "", err
+	}
+
+	defer j.Unlock()
+	j.Lock()
+
+	return j.status, nil
+}
@@ -0,0 +1,201 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	tk "github.com/vslchnk/goscheduler/task"
+)
+
+func Test_Worker_Transition(t *testing.T) {
+	j := &job{status: cnw}
+
+	if err := j.transition(sss); err == nil {
+		t.Error("Failed to detect not allowed transition: ", j.status)
+	}
+
+	for _, s := range []Status{wtnf, wtf, stp, sss, wtnf, k} {
+		if err := j.transition(s); err != nil {
+			t.Error("Failed to change status: ", err)
+		}
+	}
+
+	if err := j.transition(wtnf); err == nil {
+		t.Error("Failed to detect transition from killed: ", j.status)
+	}
+}
+
+func Test_Worker_Status(t *testing.T) {
+	worker := NewWorker()
+	name := "status"
+	release := make(chan struct{})
+
+	a, err := tk.Create(time.Hour, time.Second, 0, func(ctx context.Context) error {
+		<-release
+
+		return nil
+	})
+
+	if err != nil {
+		t.Error("Failed to create task: ", err)
+	}
+
+	if err := worker.Add(a, name); err != nil {
+		t.Error("Failed to add task to worker: ", err)
+	}
+
+	if _, err := worker.Status(name + "1"); err == nil {
+		t.Error("Failed to detect error while getting status")
+	}
+
+	if s, _ := worker.Status(name); s != StatusCreated {
+		t.Error("Wrong status of created job: ", s)
+	}
+
+	if err := worker.Start(name); err != nil {
+		t.Error("Failed to start worker: ", err)
+	}
+
+	time.Sleep(time.Millisecond * 50)
+
+	if err := worker.Stop(name); err != nil {
+		t.Error("Failed to stop worker: ", err)
+	}
+
+	if s, _ := worker.Status(name); s != StatusStopping {
+		t.Error("Wrong status of stopping job: ", s)
+	}
+
+	if err := worker.Start(name); err == nil {
+		t.Error("Failed to detect start of stopping job")
+	}
+
+	if err := worker.Delete(name); err == nil {
+		t.Error("Failed to detect deletion of stopping job")
+	}
+
+	close(release)
+	time.Sleep(time.Millisecond * 50)
+
+	if s, _ := worker.Status(name); s != StatusStopped {
+		t.Error("Wrong status of stopped job: ", s)
+	}
+
+	if _, err := worker.LastRun(name, Success); err != nil {
+		t.Error("Failed to record run finished after stop: ", err)
+	}
+}
+
+func Test_Worker_KillStopping(t *testing.T) {
+	worker := NewWorker()
+	name := "stopping"
+
+	a, err := tk.Create(time.Hour, 0, 0, func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return nil
+	})
+
+	if err != nil {
+		t.Error("Failed to create task: ", err)
+	}
+
+	if err := worker.Add(a, name); err != nil {
+		t.Error("Failed to add task to worker: ", err)
+	}
+
+	if err := worker.Start(name); err != nil {
+		t.Error("Failed to start worker: ", err)
+	}
+
+	time.Sleep(time.Millisecond * 50)
+
+	if err := worker.Stop(name); err != nil {
+		t.Error("Failed to stop worker: ", err)
+	}
+
+	if err := worker.Kill(name); err != nil {
+		t.Error("Failed to kill stopping worker: ", err)
+	}
+
+	time.Sleep(time.Millisecond * 50)
+
+	if worker.check(name) {
+		t.Error("Failed to remove killed job from pool")
+	}
+}
+
+func Test_Worker_StressStates(t *testing.T) {
+	worker := NewWorker()
+	names := []string{"a", "b", "c", "d"}
+
+	task := func() tk.Task {
+		a, err := tk.Create(time.Millisecond, time.Millisecond*5, 0, func(ctx context.Context) error {
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Millisecond * 2):
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			t.Fatal("Failed to create task: ", err)
+		}
+
+		return a
+	}
+
+	wg := sync.WaitGroup{}
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+
+			for i := 0; i < 300; i++ {
+				n := names[(g+i)%len(names)]
+
+				switch (g * i) % 8 {
+				case 0:
+					worker.Add(task(), n)
+				case 1, 2:
+					worker.Start(n)
+				case 3:
+					worker.Stop(n)
+				case 4:
+					worker.Kill(n)
+				case 5:
+					worker.Delete(n)
+				case 6:
+					worker.ChangeTask(n, task())
+				case 7:
+					worker.Status(n)
+					worker.History(n, HistoryFilter{})
+					worker.Labels(n)
+				}
+
+				if i%50 == 0 {
+					time.Sleep(time.Millisecond)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	worker.KillAll()
+	time.Sleep(time.Millisecond * 50)
+
+	for _, n := range names {
+		s, err := worker.Status(n)
+		if err != nil {
+			continue
+		}
+
+		if s.working() {
+			t.Error(fmt.Sprintf("Job %v is still working after kill: ", n), s)
+		}
+	}
+}
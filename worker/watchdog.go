@@ -0,0 +1,155 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// state of job watched by watchdog
+type WatchState struct {
+	// start of job's run which is running, zero if task isn't running
+	RunStart time.Time
+	// run is running longer than expected duration of task
+	Overrun bool
+	// end of job's last successful run
+	LastSuccess time.Time
+	// job hasn't run successfully within max staleness of task
+	Stale bool
+}
+
+// run which is running, if watchdog reported it and channel closed when watchdog abandons it
+type tracked struct {
+	rec     RunRecord
+	overrun bool
+	lost    chan struct{}
+}
+
+// returns state of job watched by watchdog by its name
+func (w *worker) Watchdog(n string) (WatchState, error) {
+	j, err := w.get(n)
+	if err != nil {
+		return WatchState{}, err
+	}
+
+	defer j.Unlock()
+	j.Lock()
+
+	start, overrun := j.current()
+
+	return WatchState{RunStart: start, Overrun: overrun, LastSuccess: j.lastSuccess, Stale: j.stale}, nil
+}
+
+// checks jobs every interval until ctx is done: reports runs longer than expected duration of their tasks, abandoning them
+// if tasks allow it, and working jobs without successful run within max staleness of their tasks
+func (w *worker) Watch(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("Interval must be greater than 0")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	w.watchBeat.Store(time.Now().UnixNano())
+	w.watchInterval.Store(int64(interval))
+	defer w.watchInterval.Store(0)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			w.watch(now)
+			w.watchBeat.Store(time.Now().UnixNano())
+		}
+	}
+}
+
+// checks all jobs at now and emits events of overrun runs and stale jobs
+func (w *worker) watch(now time.Time) {
+	w.Lock()
+	jobs := make(map[string]*job, len(w.jobs))
+	for n, j := range w.jobs {
+		jobs[n] = j
+	}
+	w.Unlock()
+
+	for n, j := range jobs {
+		j.Lock()
+		events := j.watch(n, now)
+		j.Unlock()
+
+		for _, e := range events {
+			w.emit(e)
+		}
+	}
+}
+
+// checks job at now, returns events of its overrun run and its staleness; job must be locked
+func (j *job) watch(n string, now time.Time) []Event {
+	events := []Event{}
+
+	for _, r := range j.running {
+		if expected := j.task.GetExpectedDuration(); expected > 0 && !r.overrun && now.Sub(r.rec.Start) > expected {
+			r.overrun = true
+			msg := fmt.Sprintf("Run is running for %v, expected %v", now.Sub(r.rec.Start).Round(time.Millisecond), expected)
+
+			if j.task.GetAbandonOverrun() {
+				close(r.lost)
+				msg += ", run is abandoned"
+			}
+
+			events = append(events, Event{Type: EventRunOverrun, Job: n, Time: now, Run: r.rec, Message: msg})
+		}
+	}
+
+	since := j.lastSuccess
+	if j.started.After(since) {
+		since = j.started
+	}
+
+	if staleness := j.task.GetMaxStaleness(); staleness > 0 && j.status.working() && !j.stale && now.Sub(since) > staleness {
+		j.stale = true
+		events = append(events, Event{Type: EventJobStale, Job: n, Time: now, Message: fmt.Sprintf("Job hasn't run successfully for %v", now.Sub(since).Round(time.Millisecond))})
+	}
+
+	return events
+}
+
+// marks run rec as running, returns channel which is closed when watchdog abandons it
+func (j *job) track(rec RunRecord) <-chan struct{} {
+	defer j.Unlock()
+	j.Lock()
+
+	if j.running == nil {
+		j.running = make(map[string]*tracked)
+	}
+
+	r := &tracked{rec: rec, lost: make(chan struct{})}
+	j.running[rec.RunID] = r
+
+	return r.lost
+}
+
+// marks that run rec isn't running
+func (j *job) untrack(rec RunRecord) {
+	defer j.Unlock()
+	j.Lock()
+
+	delete(j.running, rec.RunID)
+}
+
+// returns start of the earliest run which is running, zero if no run is running, and if watchdog reported any run; job must be locked
+func (j *job) current() (time.Time, bool) {
+	start, overrun := time.Time{}, false
+
+	for _, r := range j.running {
+		if start.IsZero() || r.rec.Start.Before(start) {
+			start = r.rec.Start
+		}
+
+		overrun = overrun || r.overrun
+	}
+
+	return start, overrun
+}
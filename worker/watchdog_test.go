@@ -0,0 +1,171 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tk "github.com/vslchnk/goscheduler/task"
+)
+
+func Test_Watchdog_Overrun(t *testing.T) {
+	worker := NewWorker()
+	name := "hung"
+	block := make(chan struct{})
+	defer close(block)
+	var runs atomic.Int32
+
+	// the first run ignores cancellation of its context and hangs
+	a, err := tk.Create(time.Millisecond*20, 0, 0, func(ctx context.Context) error {
+		if runs.Add(1) == 1 {
+			<-block
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	a.SetExpectedDuration(time.Millisecond * 30)
+	a.SetAbandonOverrun(true)
+	a.SetMaxRuns(3)
+
+	evs := &events{}
+	worker.Subscribe(evs.add)
+	worker.Add(a, name)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := worker.Watch(ctx, 0); err == nil {
+		t.Error("Failed to detect wrong interval")
+	}
+
+	go worker.Watch(ctx, time.Millisecond*5)
+	worker.Start(name)
+
+	time.Sleep(time.Millisecond * 20)
+
+	if s, _ := worker.Watchdog(name); s.RunStart.IsZero() || s.Overrun {
+		t.Error("Wrong state of running job: ", s)
+	}
+
+	time.Sleep(time.Millisecond * 130)
+
+	history, _ := worker.History(name, HistoryFilter{})
+	if len(history) != 3 || history[2].Outcome != Lost || history[1].Outcome != Success {
+		t.Fatal("Failed to abandon hung run: ", history)
+	}
+
+	if s, _ := worker.Watchdog(name); !s.RunStart.IsZero() || s.Overrun || s.LastSuccess.IsZero() {
+		t.Error("Wrong state of job after runs: ", s)
+	}
+
+	if fmt.Sprint(evs.types()) != "[run overrun]" {
+		t.Error("Wrong events: ", evs.types())
+	}
+}
+
+func Test_Watchdog_Chained(t *testing.T) {
+	worker := NewWorker()
+	block := make(chan struct{})
+	defer close(block)
+
+	first, _ := tk.Create(time.Millisecond*10, time.Second, 0, func(ctx context.Context) error {
+		return nil
+	})
+	first.SetMaxRuns(1)
+
+	// chained run ignores cancellation of its context and hangs
+	hung, _ := tk.Create(time.Minute, 0, 0, func(ctx context.Context) error {
+		<-block
+		return nil
+	})
+	hung.SetExpectedDuration(time.Millisecond * 30)
+	hung.SetAbandonOverrun(true)
+
+	worker.Add(first, "first")
+	worker.Add(hung, "hung")
+
+	if err := worker.Chain("first", "hung"); err != nil {
+		t.Fatal("Failed to chain jobs: ", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go worker.Watch(ctx, time.Millisecond*5)
+	worker.Start("first")
+
+	time.Sleep(time.Millisecond * 30)
+
+	if s, _ := worker.Watchdog("hung"); s.RunStart.IsZero() {
+		t.Error("Chained run isn't watched: ", s)
+	}
+
+	time.Sleep(time.Millisecond * 50)
+
+	history, _ := worker.History("hung", HistoryFilter{})
+	if len(history) != 1 || history[0].Outcome != Lost {
+		t.Error("Failed to abandon hung chained run: ", history)
+	}
+
+	if s, _ := worker.Watchdog("hung"); !s.RunStart.IsZero() || s.Overrun {
+		t.Error("Wrong state of job after abandoned run: ", s)
+	}
+}
+
+func Test_Watchdog_Stale(t *testing.T) {
+	worker := NewWorker()
+	name := "stale"
+	var failing atomic.Bool
+	failing.Store(true)
+
+	a, _ := tk.Create(time.Millisecond*10, time.Second, 0, func(ctx context.Context) error {
+		if failing.Load() {
+			return fmt.Errorf("Source is empty")
+		}
+
+		return nil
+	})
+
+	a.SetMaxStaleness(time.Millisecond * 50)
+
+	evs := &events{}
+	worker.Subscribe(evs.add)
+	worker.Add(a, name)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go worker.Watch(ctx, time.Millisecond*5)
+
+	time.Sleep(time.Millisecond * 80)
+
+	if s, _ := worker.Watchdog(name); s.Stale {
+		t.Error("Job which isn't working is stale")
+	}
+
+	worker.Start(name)
+	time.Sleep(time.Millisecond * 80)
+
+	if s, _ := worker.Watchdog(name); !s.Stale || !s.LastSuccess.IsZero() {
+		t.Error("Failed to detect stale job: ", s)
+	}
+
+	failing.Store(false)
+	time.Sleep(time.Millisecond * 30)
+	worker.Stop(name)
+
+	if s, _ := worker.Watchdog(name); s.Stale || s.LastSuccess.IsZero() {
+		t.Error("Job is stale after successful run: ", s)
+	}
+
+	if fmt.Sprint(evs.types()) != "[job stale]" {
+		t.Error("Wrong events: ", evs.types())
+	}
+}
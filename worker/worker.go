@@ -2,44 +2,104 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/vslchnk/goscheduler/lock"
+	"github.com/vslchnk/goscheduler/queue"
+	"github.com/vslchnk/goscheduler/ratelimit"
 	t "github.com/vslchnk/goscheduler/task"
 )
 
-type jobstatus string
-
-const (
-	sss  jobstatus = "stopped by stop signal"
-	cnw  jobstatus = "created, not working"
-	wtnf jobstatus = "working, task isn't finished"
-	wtf  jobstatus = "working, task is finished"
-	ste  jobstatus = "stopped, time has expired"
-	k    jobstatus = "killed"
-)
-
 type worker struct {
 	sync.Mutex
-	jobs map[string]*job
+	jobs          map[string]*job
+	historySize   int
+	historyMaxAge time.Duration
+	rand          *rand.Rand
+	workflows     map[string]*workflow
+	workflowRuns  map[string]*workflowRun
+	locker        lock.Locker
+	lockTTL       time.Duration
+	leader        *lock.Leader
+	queue         queue.Queue
+	handlers      []func(Event)
+	restoring     bool
+	rateGroups    map[string]*ratelimit.Bucket
+	dispatcher    *dispatcher
+	// interval of running watchdog loop and time of its last tick in nanoseconds, read without locking worker
+	watchInterval atomic.Int64
+	watchBeat     atomic.Int64
 }
 
+// job of worker, worker is always locked before its job, never the other way round
 type job struct {
 	sync.Mutex
-	task      t.Task
-	ctx       context.Context
-	cancelCtx context.CancelFunc
-	status    jobstatus
+	task       t.Task
+	cancelStop context.CancelFunc
+	cancelKill context.CancelFunc
+	status     Status
+	history    *history
+	runs       int
+	next       string
+	onFailure  string
+	labels     map[string]string
+	// run working job waits for, the next run planned by its schedule and number of runs left after them, negative if unlimited
+	pending  slot
+	upcoming time.Time
+	left     int
+	breaker  BreakerState
+	reset    chan struct{}
+	// runs which are running by their IDs
+	running map[string]*tracked
+	// time job was started at, end of its last successful run and if watchdog reported it as stale
+	started     time.Time
+	lastSuccess time.Time
+	stale       bool
+	// token bucket of rate limit of job's task, nil if task isn't limited
+	bucket *ratelimit.Bucket
 }
 
 // creates new worker
 func NewWorker() *worker {
 	w := worker{}
 	w.jobs = make(map[string]*job)
+	w.historySize = DefaultHistorySize
+	w.historyMaxAge = DefaultHistoryMaxAge
+	w.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	w.workflows = make(map[string]*workflow)
+	w.workflowRuns = make(map[string]*workflowRun)
+	w.rateGroups = make(map[string]*ratelimit.Bucket)
+	w.dispatcher = newDispatcher()
 	return &w
 }
 
+// sets source of random jitter of tasks' runs
+func (w *worker) SetRandSource(src rand.Source) error {
+	if src == nil {
+		return fmt.Errorf("No random source provided")
+	}
+
+	defer w.Unlock()
+	w.Lock()
+
+	w.rand = rand.New(src)
+
+	return nil
+}
+
+// returns offset of job's run planned at planned from its schedule: job's splay and jitter
+func (w *worker) offset(n string, task t.Task, planned time.Time) time.Duration {
+	defer w.Unlock()
+	w.Lock()
+
+	return task.Offset(n, planned, w.rand)
+}
+
 // checks if there is a job with name n in job pool
 func (w *worker) check(n string) bool {
 	defer w.Unlock()
@@ -50,44 +110,119 @@ func (w *worker) check(n string) bool {
 	return ok
 }
 
+// returns job with name n from job pool
+func (w *worker) get(n string) (*job, error) {
+	defer w.Unlock()
+	w.Lock()
+
+	j, ok := w.jobs[n]
+	if !ok {
+		return nil, jobError(n, ErrJobNotFound)
+	}
+
+	return j, nil
+}
+
 // change task in job pool by its name
 func (w *worker) ChangeTask(n string, task t.Task) error {
-	if !w.check(n) {
-		return fmt.Errorf("No job with name %v in job pool", n)
+	j, err := w.get(n)
+	if err != nil {
+		return err
 	}
 
-	defer w.jobs[n].Unlock()
-	w.jobs[n].Lock()
+	defer j.Unlock()
+	j.Lock()
 
-	if w.jobs[n].status == wtnf || w.jobs[n].status == wtf {
-		return fmt.Errorf("Job is working, must be stopped before being changed")
+	if j.status.working() {
+		return j.error(n, ErrJobRunning)
 	}
 
-	w.jobs[n].task = task
+	j.task = task
 
 	return nil
 }
 
+// returns task of job by its name
+func (w *worker) GetTask(n string) (t.Task, error) {
+	j, err := w.get(n)
+	if err != nil {
+		return t.Task{}, err
+	}
+
+	defer j.Unlock()
+	j.Lock()
+
+	return j.task, nil
+}
+
+// returns the next count fires of job by its name, job which isn't working is previewed as if it started now
+func (w *worker) NextRuns(n string, count int) ([]t.Fire, error) {
+	if count <= 0 {
+		return nil, fmt.Errorf("Number of runs must be greater than 0")
+	}
+
+	j, err := w.get(n)
+	if err != nil {
+		return nil, err
+	}
+
+	j.Lock()
+	task, working, pending, upcoming, left := j.task, j.status.working(), j.pending, j.upcoming, j.left
+	j.Unlock()
+
+	now := time.Now()
+	if !working || pending.fire.IsZero() {
+		return task.Preview(n, now, count), nil
+	}
+
+	fires := []t.Fire{}
+	if !pending.skip && pending.fire.After(now) {
+		fires = append(fires, t.Fire{Planned: pending.planned, At: pending.fire, Exact: true, Reason: pending.reason})
+	}
+
+	if upcoming.IsZero() || left == 0 || len(fires) == count {
+		return fires, nil
+	}
+
+	// the rest of runs follows the next planned one
+	task.SetStartTime(upcoming)
+	if left > 0 {
+		task.SetMaxRuns(left)
+	}
+
+	return append(fires, task.Preview(n, now, count-len(fires))...), nil
+}
+
 // adds task to job pool, if name n of job is unique, if ok return number of job in job pool, if not return number of job with the same name and error
 func (w *worker) Add(task t.Task, n string) error {
+	defer w.Unlock()
+	w.Lock()
 
-	if w.check(n) {
-		return fmt.Errorf("Function with name %v already exist", n)
+	if _, ok := w.jobs[n]; ok {
+		return jobError(n, ErrJobExists)
 	}
 
-	j := &job{task: task, status: cnw}
+	h, err := newHistory(w.historySize, w.historyMaxAge)
+	if err != nil {
+		return err
+	}
 
-	w.Lock()
-	w.jobs[n] = j
-	w.Unlock()
+	w.jobs[n] = &job{task: task, status: cnw, history: h, breaker: BreakerState{State: CircuitClosed}, reset: make(chan struct{}, 1)}
 
 	return nil
 }
 
 // prints jobs in job pool
-func (w worker) PrintAll() error {
-	for k, _ := range w.jobs {
-		if err := w.Print(k); err != nil {
+func (w *worker) PrintAll() error {
+	w.Lock()
+	names := make([]string, 0, len(w.jobs))
+	for n := range w.jobs {
+		names = append(names, n)
+	}
+	w.Unlock()
+
+	for _, n := range names {
+		if err := w.Print(n); err != nil {
 			return fmt.Errorf("Error in PrintAll(): %v", err)
 		}
 	}
@@ -96,212 +231,625 @@ func (w worker) PrintAll() error {
 }
 
 // prints job from job pool by its name
-func (w worker) Print(n string) error {
-	if !w.check(n) {
-		return fmt.Errorf("No job with name %v in job pool", n)
+func (w *worker) Print(n string) error {
+	j, err := w.get(n)
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf("Name: %v; Status: %v; ", n, w.jobs[n].status)
-	w.jobs[n].task.Print()
+	defer j.Unlock()
+	j.Lock()
+
+	fmt.Printf("Name: %v; Status: %v; Labels: %v; ", n, j.status, j.labels)
+	j.task.Print()
 
 	return nil
 }
 
 // starts job by its name
 func (w *worker) Start(n string) error {
-	if !w.check(n) {
-		return fmt.Errorf("No job with name %v in job pool", n)
+	w.Lock()
+
+	j, ok := w.jobs[n]
+	if !ok {
+		w.Unlock()
+		return jobError(n, ErrJobNotFound)
 	}
 
-	w.jobs[n].Lock()
+	// task is validated under job's lock so it can't be changed before job is started
+	j.Lock()
+	err := w.checkGroup(j.task)
+	w.Unlock()
+
+	defer j.Unlock()
 
-	if w.jobs[n].status == wtnf || w.jobs[n].status == wtf {
-		w.jobs[n].Unlock()
-		return fmt.Errorf("Job name %v is already working, its status: %v", n, w.jobs[n].status)
+	if err != nil {
+		return jobError(n, err)
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	if j.status.working() {
+		return j.error(n, ErrJobRunning)
+	}
+
+	bucket, err := jobBucket(j.task)
+	if err != nil {
+		return j.error(n, err)
+	}
+
+	if err := j.transition(wtnf); err != nil {
+		return j.error(n, err)
+	}
+
+	j.bucket = bucket
 
-	w.jobs[n].status = wtnf
-	w.jobs[n].ctx = ctx
-	w.jobs[n].cancelCtx = cancel
-	w.jobs[n].Unlock()
+	kill, cancelKill := context.WithCancel(context.Background())
+	stop, cancelStop := context.WithCancel(kill)
 
-	go w.startJob(n)
+	j.cancelStop = cancelStop
+	j.cancelKill = cancelKill
+	j.pending, j.upcoming = slot{}, time.Time{}
+	j.started, j.stale = time.Now(), false
+	go w.startJob(n, j, stop, kill, j.task)
 
 	return nil
 }
 
-// starts all jobs if they are stopped or not started
+// starts all jobs if they are stopped or not started, tries every job and returns errors of all failed ones
 func (w *worker) StartAll() error {
-	for k, _ := range w.jobs {
-		if err := w.Start(k); err != nil {
-			return fmt.Errorf("Error in StartAll(): %v", err)
-		}
-	}
-
-	return nil
+	return w.bulk("StartAll", "", w.Start)
 }
 
-// stops job by its name
+// stops job by its name, job is stopping until its running task finishes; returns ErrJobStopping if job is already stopping
 func (w *worker) Stop(n string) error {
-	if !w.check(n) {
-		return fmt.Errorf("No job with name %v in job pool", n)
+	j, err := w.get(n)
+	if err != nil {
+		return err
 	}
 
-	w.jobs[n].Lock()
-	if w.jobs[n].status == sss || w.jobs[n].status == ste || w.jobs[n].status == k || w.jobs[n].status == cnw {
-		w.jobs[n].Unlock()
-		return fmt.Errorf("Job name %v is not working, its status: %v", n, w.jobs[n].status)
+	defer j.Unlock()
+	j.Lock()
+
+	if j.status == stp {
+		return j.error(n, ErrJobStopping)
 	}
-	w.jobs[n].status = sss
-	w.jobs[n].cancelCtx()
-	w.jobs[n].Unlock()
 
-	return nil
-}
+	if j.status != wtnf && j.status != wtf && j.status != sus {
+		return j.error(n, ErrJobNotRunning)
+	}
 
-// stops all jobs if they are not stopped or not started
-func (w *worker) StopAll() error {
-	for k, _ := range w.jobs {
-		if err := w.Stop(k); err != nil {
-			return fmt.Errorf("Error in StopAll(): %v", err)
-		}
+	if err := j.transition(stp); err != nil {
+		return j.error(n, err)
 	}
+	j.cancelStop()
 
 	return nil
 }
 
+// stops all jobs if they are not stopped or not started, tries every job and returns errors of all failed ones
+func (w *worker) StopAll() error {
+	return w.bulk("StopAll", "", w.Stop)
+}
+
 // kills job and removes it from pool
 func (w *worker) Kill(n string) error {
-	if !w.check(n) {
-		return fmt.Errorf("No job with name %v in job pool", n)
+	j, err := w.get(n)
+	if err != nil {
+		return err
 	}
 
-	w.jobs[n].Lock()
+	defer j.Unlock()
+	j.Lock()
 
-	if w.jobs[n].status == sss || w.jobs[n].status == ste || w.jobs[n].status == k || w.jobs[n].status == cnw {
-		w.jobs[n].Unlock()
-		return fmt.Errorf("Job name %v is not working, its status: %v", n, w.jobs[n].status)
+	if !j.status.working() {
+		return j.error(n, ErrJobNotRunning)
 	}
 
-	w.jobs[n].status = k
-	w.jobs[n].cancelCtx()
-	w.jobs[n].Unlock()
+	if err := j.transition(k); err != nil {
+		return j.error(n, err)
+	}
+	j.cancelKill()
 
 	return nil
 }
 
-// kills all jobs and removes them from pool
+// kills all jobs and removes them from pool, tries every job and returns errors of all failed ones
 func (w *worker) KillAll() error {
-	for k, _ := range w.jobs {
-		if err := w.Kill(k); err != nil {
-			return fmt.Errorf("Error in KillAll(): %v", err)
-		}
-	}
-
-	return nil
+	return w.bulk("KillAll", "", w.Kill)
 }
 
 // delets job from pool by its number
 func (w *worker) Delete(n string) error {
-	if !w.check(n) {
-		return fmt.Errorf("No job with name %v in job pool", n)
+	defer w.Unlock()
+	w.Lock()
+
+	j, ok := w.jobs[n]
+	if !ok {
+		return jobError(n, ErrJobNotFound)
 	}
 
-	w.Lock()
-	if w.jobs[n].status == wtf || w.jobs[n].status == wtnf {
-		w.Unlock()
-		return fmt.Errorf("Job name %v is working, its status: %v", n, w.jobs[n].status)
+	defer j.Unlock()
+	j.Lock()
+
+	if j.status.working() {
+		return j.error(n, ErrJobRunning)
+	}
+
+	if wf := w.workflowOf(n); wf != "" {
+		return j.error(n, fmt.Errorf("%w %v", ErrJobInWorkflow, wf))
 	}
 
 	delete(w.jobs, n)
-	w.Unlock()
 
 	return nil
 }
 
-// controls work of job
-func (w *worker) startJob(n string) {
-	defer w.deleteKilled(n)
-	defer w.jobs[n].cancelCtx()
+// controls work of job, stop is cancelled when job is stopped, kill when job is killed
+func (w *worker) startJob(n string, j *job, stop context.Context, kill context.Context, task t.Task) {
+	w.release(n, j, w.runJob(n, j, stop, kill, task))
+}
+
+// runs job's task by its schedule, returns status job ends with if it isn't stopped or killed
+func (w *worker) runJob(n string, j *job, stop context.Context, kill context.Context, task t.Task) Status {
+	now := time.Now()
+	next := task.First(now)
+	catchUp := []time.Time{}
+
+	for runs := 0; ; runs, now = runs+1, time.Now() {
+		if j.open() {
+			if final, ended := w.suspend(n, j, stop); ended {
+				return final
+			}
+			now = time.Now()
+		}
+
+		if len(catchUp) == 0 {
+			// run is late only after the latest time its splay and jitter can move it to
+			catchUp, next = task.Missed(next, now.Add(-task.MaxOffset(n)))
+		}
+
+		planned, missed := next, len(catchUp) > 0
+		if missed {
+			planned, catchUp = catchUp[0], catchUp[1:]
+		}
+
+		if planned.IsZero() || (task.GetMaxRuns() > 0 && runs >= task.GetMaxRuns()) {
+			return cmp
+		}
+
+		fire := planned
+		if !missed {
+			fire = planned.Add(w.offset(n, task, planned))
+			next = task.Next(next)
+		}
+
+		s := window(task, slot{planned: fire, fire: fire, catchUp: missed})
+		if s.fire.After(fire) {
+			next = task.After(next, s.fire)
+		}
+
+		// lease of run is kept half way to the next one, replicas firing the run later by their jitter skip it
+		following, splay := next, task.Splay(n)
+		if len(catchUp) > 0 {
+			following = catchUp[0]
+		}
+
+		base := planned.Add(splay)
+		if s.fire.After(fire) {
+			base = s.fire
+		}
+
+		if following.Add(splay).After(base) {
+			s.hold = base.Add(following.Add(splay).Sub(base) / 2)
+		}
+
+		j.Lock()
+		j.pending, j.upcoming, j.left = s, next, -1
+		if task.GetMaxRuns() > 0 {
+			j.left = task.GetMaxRuns() - runs - 1
+		}
+		j.Unlock()
+
+		timer := time.NewTimer(time.Until(s.fire))
+		select {
+		case <-stop.Done():
+			timer.Stop()
+
+			return sss
+		case <-timer.C:
+		}
+
+		if !s.skip {
+			var ok bool
+			if s, ok = w.throttle(n, j, stop, task, s); !ok {
+				return sss
+			}
+		}
+
+		if s.skip {
+			w.finished(n, j.skip(n, s), nil)
+			continue
+		}
+
+		if final, ended := w.run(n, j, stop, kill, task, s); ended {
+			return final
+		}
+	}
+}
+
+// run of job planned by its schedule
+type slot struct {
+	planned time.Time
+	fire    time.Time
+	catchUp bool
+	skip    bool
+	outcome Outcome
+	reason  string
+	// time lease of run is kept until, zero if it's kept for ttl of locker
+	hold time.Time
+}
+
+// applies task's windows to run s, run outside of them is deferred to the next allowed time or skipped
+func window(task t.Task, s slot) slot {
+	at := s.fire
+	if now := time.Now(); at.Before(now) {
+		at = now
+	}
+
+	fire, ok, reason := task.Fit(at)
+	if reason != "" {
+		s.fire, s.skip, s.reason = fire, !ok, reason
+		if !ok {
+			s.outcome = Blocked
+		}
+	}
+
+	return s
+}
+
+// runs job's task once for its run s holding leases of run, returns status job ends with and true if job must end
+func (w *worker) run(n string, j *job, stop context.Context, kill context.Context, task t.Task, s slot) (Status, bool) {
+	ctx, release, err := w.lease(n, s.hold)
+	if err != nil {
+		if !errors.Is(err, lock.ErrLocked) && !errors.Is(err, errNotLeader) {
+			w.unleased(n, j, s, err)
+		}
+
+		return "", false
+	}
+	defer release()
+
+	if s.catchUp {
+		ctx = t.WithCatchUp(ctx)
+	}
+
+	j.Lock()
+	err = j.transition(wtnf)
+	j.Unlock()
+	if err != nil {
+		return sss, true
+	}
+
+	if w.enqueue(ctx, n, j, task, s) {
+		return "", false
+	}
 
-	delayChan := time.NewTimer(time.Duration(w.jobs[n].task.GetDelay())).C
-	for {
+	release, wait, ok := w.acquire(stop, task)
+	if !ok {
+		return sss, true
+	}
+	defer release()
+
+	rec := j.begin(n, s, "")
+	rec.QueueWait = wait
+	done, cancel := launch(ctx, task, nil, stop.Done())
+	expiredChan := expired(task)
+	lost := j.track(rec)
+	defer j.untrack(rec)
+
+	select {
+	case <-stop.Done():
 		select {
-		case <-w.jobs[n].ctx.Done():
-
-			return
-		case <-delayChan:
-
-			tickChan := time.NewTimer(0).C
-			for {
-				select {
-				case <-w.jobs[n].ctx.Done():
-
-					return
-				case <-tickChan:
-
-					c2, cancel := context.WithCancel(context.Background())
-					c1 := context.WithValue(c2, "func", cancel)
-
-					go w.jobs[n].task.GetDoFunc()(c1)
-
-					tickChan = time.NewTimer(time.Duration(w.jobs[n].task.GetPeriod())).C
-					expiredChan := time.NewTimer(time.Duration(w.jobs[n].task.GetTaskTime())).C
-				Looptick:
-					for {
-						select {
-						case <-w.jobs[n].ctx.Done():
-
-							w.jobs[n].Lock()
-							if w.jobs[n].status == k {
-								cancel()
-							} else {
-							Loopfinished:
-								for {
-									select {
-									case <-c2.Done():
-										break Loopfinished
-									}
-								}
-							}
-							w.jobs[n].Unlock()
-
-							return
-						case <-expiredChan:
-							cancel()
-							w.jobs[n].Lock()
-							w.jobs[n].status = ste
-							w.jobs[n].Unlock()
-
-							return
-						case <-c2.Done():
-							w.jobs[n].Lock()
-							w.jobs[n].status = wtf
-							w.jobs[n].Unlock()
-
-							break Looptick
-						}
-					}
-					fmt.Println("break looptick")
-				}
+		case <-lost:
+			cancel()
+			w.abandoned(n, j, rec)
+		case res := <-done:
+			j.Lock()
+			rec = j.finish(rec, res)
+			j.Unlock()
+			w.finished(n, rec, res.err)
+			if rec.Outcome == Success {
+				w.triggerWorkflows(n, rec)
 			}
+		case <-kill.Done():
+			rec = interrupt(rec, task, done, cancel)
+			err := fmt.Errorf("Job is killed")
+			j.Lock()
+			rec = j.end(rec, Killed, err)
+			j.Unlock()
+			w.finished(n, rec, err)
+		case <-expiredChan:
+			rec = interrupt(rec, task, done, cancel)
+			err := fmt.Errorf("Task time has expired")
+			j.Lock()
+			rec = j.end(rec, Timeout, err)
+			j.Unlock()
+			w.finished(n, rec, err)
+		}
+
+		return sss, true
+	case <-expiredChan:
+		rec = interrupt(rec, task, done, cancel)
+		err := fmt.Errorf("Task time has expired")
+		j.Lock()
+		rec = j.end(rec, Timeout, err)
+		j.Unlock()
+		w.finished(n, rec, err)
+
+		return ste, true
+	case <-lost:
+		cancel()
+		j.Lock()
+		j.transition(wtf)
+		j.Unlock()
+		w.abandoned(n, j, rec)
+	case res := <-done:
+		j.Lock()
+		j.transition(wtf)
+		rec = j.finish(rec, res)
+		e, tripped := j.trip(n, task, rec)
+		j.Unlock()
+
+		w.finished(n, rec, res.err)
+		if tripped {
+			w.emit(e)
+		}
+
+		if rec.Outcome == Success {
+			w.triggerWorkflows(n, rec)
 		}
 	}
+
+	return "", false
 }
 
-// deletes job if it's killed from pool
-func (w *worker) deleteKilled(n string) error {
-	if !w.check(n) {
-		return fmt.Errorf("No job with name %v in job pool", n)
+// records run rec abandoned by watchdog as lost and returns its record, its task's function is left running
+func (w *worker) abandoned(n string, j *job, rec RunRecord) RunRecord {
+	err := fmt.Errorf("Run is abandoned by watchdog")
+	j.Lock()
+	rec = j.end(rec, Lost, err)
+	j.Unlock()
+	w.finished(n, rec, err)
+
+	return rec
+}
+
+// moves job which work has ended to its final status, deletes it from pool if it's killed or if it's completed and its task is auto deleted
+func (w *worker) release(n string, j *job, final Status) {
+	defer w.Unlock()
+	w.Lock()
+
+	defer j.Unlock()
+	j.Lock()
+
+	j.cancelKill()
+
+	switch j.status {
+	case k:
+	case stp:
+		j.transition(sss)
+	default:
+		j.transition(final)
+	}
+
+	w.deleteEnded(n, j)
+}
+
+// starts new run record of job for its run s, parent is ID of run which triggered this one
+func (j *job) begin(n string, s slot, parent string) RunRecord {
+	defer j.Unlock()
+	j.Lock()
+
+	j.runs++
+
+	r := RunRecord{RunID: fmt.Sprintf("%v-%d", n, j.runs), Job: n, PlannedStart: s.planned, Start: time.Now(), Attempt: 1, ParentRunID: parent, CatchUp: s.catchUp, Reason: s.reason}
+	if r.Start.After(r.PlannedStart) {
+		r.Lateness = r.Start.Sub(r.PlannedStart)
 	}
 
-	if w.jobs[n].status == k {
-		if err := w.Delete(n); err != nil {
-			return fmt.Errorf("Error in deleteKilled(): %v", err)
+	return r
+}
+
+// records skipped run s of job in its history
+func (j *job) skip(n string, s slot) RunRecord {
+	rec := j.begin(n, s, "")
+
+	defer j.Unlock()
+	j.Lock()
+
+	return j.end(rec, s.outcome, nil)
+}
+
+// completes run record by result of task's function, job must be locked
+func (j *job) finish(r RunRecord, res result) RunRecord {
+	r.Result = res.value
+
+	if res.err != nil {
+		return j.end(r, Failure, res.err)
+	}
+
+	return j.end(r, Success, nil)
+}
+
+// completes run record with outcome o and adds it to job's history, job must be locked
+func (j *job) end(r RunRecord, o Outcome, err error) RunRecord {
+	r.End = time.Now()
+	r.Duration = r.End.Sub(r.Start)
+	r.Outcome = o
+
+	if err != nil {
+		r.Err = err.Error()
+	}
+
+	if o == Success {
+		j.lastSuccess, j.stale = r.End, false
+	}
+
+	j.history.add(r)
+
+	return r
+}
+
+// returns channel which fires when task time of run has expired, nil channel if task time isn't limited
+func expired(task t.Task) <-chan time.Time {
+	if task.GetTaskTime() == 0 {
+		return nil
+	}
+
+	return time.NewTimer(task.GetTaskTime()).C
+}
+
+// result of task's function
+type result struct {
+	value interface{}
+	err   error
+}
+
+// starts task's function with input under ctx, stop is closed when job is stopped; returns channel with its result and func cancelling its context
+func launch(ctx context.Context, task t.Task, input interface{}, stop <-chan struct{}) (<-chan result, context.CancelFunc) {
+	c2, cancel := context.WithCancel(ctx)
+	c1 := context.WithValue(c2, "func", cancel)
+
+	if input != nil {
+		c1 = t.WithInput(c1, input)
+	}
+
+	if stop != nil {
+		c1 = t.WithStopSignal(c1, stop)
+	}
+
+	done := make(chan result, 1)
+	do := task.GetResultFunc()
+	go func() {
+		v, err := do(c1)
+		done <- result{value: v, err: err}
+		cancel()
+	}()
+
+	return done, cancel
+}
+
+// cancels run rec of task and waits up to task's wait delay for its result, so output of cancelled command is kept in rec
+func interrupt(rec RunRecord, task t.Task, done <-chan result, cancel context.CancelFunc) RunRecord {
+	cancel()
+
+	if d := task.GetWaitDelay(); d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+
+		select {
+		case res := <-done:
+			rec.Result = res.value
+		case <-timer.C:
 		}
 	}
 
+	return rec
+}
+
+// runs job's task once outside of its schedule with input and records run in job's history, parent is ID of run which triggered this one
+func (w *worker) runOnce(n string, input interface{}, parent string) (RunRecord, error) {
+	j, err := w.get(n)
+	if err != nil {
+		return RunRecord{}, err
+	}
+
+	j.Lock()
+	task := j.task
+	j.Unlock()
+
+	return w.execute(context.Background(), n, j, task, input, parent), nil
+}
+
+// runs task of job j once under ctx with input limited by rate limits of job and its group, records run in job's history
+// and triggers jobs chained to it
+func (w *worker) execute(ctx context.Context, n string, j *job, task t.Task, input interface{}, parent string) RunRecord {
+	s, ok := w.throttle(n, j, ctx, task, slot{planned: time.Now()})
+	if s.skip {
+		rec := j.begin(n, s, parent)
+		j.Lock()
+		rec = j.end(rec, s.outcome, nil)
+		j.Unlock()
+		w.finished(n, rec, nil)
+
+		return rec
+	}
+
+	release, wait := func() {}, time.Duration(0)
+	if ok {
+		release, wait, ok = w.acquire(ctx, task)
+	}
+
+	if !ok {
+		rec := j.begin(n, s, parent)
+		err := fmt.Errorf("Run is cancelled before it's started")
+		j.Lock()
+		rec = j.end(rec, Killed, err)
+		j.Unlock()
+		w.finished(n, rec, err)
+
+		return rec
+	}
+	defer release()
+
+	rec := j.begin(n, s, parent)
+	rec.QueueWait = wait
+	done, cancel := launch(ctx, task, input, nil)
+	defer cancel()
+	lost := j.track(rec)
+	defer j.untrack(rec)
+
+	select {
+	case <-lost:
+		cancel()
+		rec = w.abandoned(n, j, rec)
+	case res := <-done:
+		j.Lock()
+		rec = j.finish(rec, res)
+		j.Unlock()
+		w.finished(n, rec, res.err)
+	case <-expired(task):
+		rec = interrupt(rec, task, done, cancel)
+		err := fmt.Errorf("Task time has expired")
+		j.Lock()
+		rec = j.end(rec, Timeout, err)
+		j.Unlock()
+		w.finished(n, rec, err)
+	}
+
+	return rec
+}
+
+// deletes job from pool if it's killed or if it's completed and its task is auto deleted
+func (w *worker) deleteKilled(n string) error {
+	defer w.Unlock()
+	w.Lock()
+
+	j, ok := w.jobs[n]
+	if !ok {
+		return jobError(n, ErrJobNotFound)
+	}
+
+	defer j.Unlock()
+	j.Lock()
+
+	w.deleteEnded(n, j)
+
 	return nil
 }
+
+// deletes job j with name n from pool if it's killed or if it's completed and its task is auto deleted, worker and job must be locked
+func (w *worker) deleteEnded(n string, j *job) {
+	if w.jobs[n] == j && (j.status == k || (j.status == cmp && j.task.GetAutoDelete())) {
+		delete(w.jobs, n)
+	}
+}
@@ -2,7 +2,13 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -987,3 +993,627 @@ func Test_Worker_DeleteKilledError(t *testing.T) {
 		t.Error("Failed to detect error while deleting killed: ", err)
 	}
 }
+
+func Test_Worker_StartOnce(t *testing.T) {
+	worker := NewWorker()
+	name := "once"
+	runs := make(chan struct{}, 10)
+
+	a, err := tk.CreateOnceAfter(time.Millisecond*20, time.Second, func(ctx context.Context) error {
+		runs <- struct{}{}
+		return nil
+	})
+
+	if err != nil {
+		t.Error("Failed to create task: ", err)
+	}
+
+	if err := worker.Add(a, name); err != nil {
+		t.Error("Failed to add task to worker: ", err)
+	}
+
+	if err := worker.Start(name); err != nil {
+		t.Error("Failed to start worker: ", err)
+	}
+
+	time.Sleep(time.Millisecond * 200)
+
+	if len(runs) != 1 {
+		t.Error("Wrong number of runs: ", len(runs))
+	}
+
+	worker.jobs[name].Lock()
+	status := worker.jobs[name].status
+	worker.jobs[name].Unlock()
+
+	if status != cmp {
+		t.Error("Failed to complete job: ", status)
+	}
+
+	if err := worker.Stop(name); err == nil {
+		t.Error("Failed to detect error while stopping completed job")
+	}
+
+	if err := worker.Delete(name); err != nil {
+		t.Error("Failed to delete completed job: ", err)
+	}
+}
+
+func Test_Worker_StartMaxRunsAutoDelete(t *testing.T) {
+	worker := NewWorker()
+	name := "limited"
+	runs := make(chan struct{}, 10)
+
+	a, err := tk.Create(time.Millisecond*20, time.Second, 0, func(ctx context.Context) error {
+		runs <- struct{}{}
+		return nil
+	})
+
+	if err != nil {
+		t.Error("Failed to create task: ", err)
+	}
+
+	a.SetMaxRuns(3)
+	a.SetAutoDelete(true)
+
+	if err := worker.Add(a, name); err != nil {
+		t.Error("Failed to add task to worker: ", err)
+	}
+
+	if err := worker.Start(name); err != nil {
+		t.Error("Failed to start worker: ", err)
+	}
+
+	time.Sleep(time.Millisecond * 200)
+
+	if len(runs) != 3 {
+		t.Error("Wrong number of runs: ", len(runs))
+	}
+
+	if worker.check(name) {
+		t.Error("Failed to delete completed job")
+	}
+}
+
+func Test_Worker_StartEndTime(t *testing.T) {
+	worker := NewWorker()
+	name := "ending"
+	runs := make(chan struct{}, 100)
+
+	a, err := tk.Create(time.Millisecond*40, time.Second, 0, func(ctx context.Context) error {
+		runs <- struct{}{}
+		return nil
+	})
+
+	if err != nil {
+		t.Error("Failed to create task: ", err)
+	}
+
+	a.SetEndTime(time.Now().Add(time.Millisecond * 100))
+
+	if err := worker.Add(a, name); err != nil {
+		t.Error("Failed to add task to worker: ", err)
+	}
+
+	if err := worker.Start(name); err != nil {
+		t.Error("Failed to start worker: ", err)
+	}
+
+	time.Sleep(time.Millisecond * 300)
+
+	if len(runs) != 3 {
+		t.Error("Wrong number of runs: ", len(runs))
+	}
+
+	worker.jobs[name].Lock()
+	status := worker.jobs[name].status
+	worker.jobs[name].Unlock()
+
+	if status != cmp {
+		t.Error("Failed to complete job: ", status)
+	}
+}
+
+func Test_Worker_StartAligned(t *testing.T) {
+	align := time.Millisecond * 100
+	planned := make(chan time.Time, 2)
+
+	time.Sleep(align - time.Duration(time.Now().UnixNano()%int64(align)) + align/10)
+
+	for i := 0; i < 2; i++ {
+		worker := NewWorker()
+		name := "aligned"
+
+		a, err := tk.Create(align, time.Second, 0, func(ctx context.Context) error {
+			return nil
+		})
+
+		if err != nil {
+			t.Error("Failed to create task: ", err)
+		}
+
+		a.SetMaxRuns(1)
+		a.SetAlignment(align)
+
+		if err := worker.Add(a, name); err != nil {
+			t.Error("Failed to add task to worker: ", err)
+		}
+
+		if err := worker.Start(name); err != nil {
+			t.Error("Failed to start worker: ", err)
+		}
+
+		go func() {
+			time.Sleep(align * 3)
+			r, err := worker.LastRun(name, "")
+			if err != nil {
+				t.Error("Failed to get last run: ", err)
+			}
+			planned <- r.PlannedStart
+		}()
+
+		time.Sleep(align / 10)
+	}
+
+	a, b := <-planned, <-planned
+
+	if !a.Equal(b) || a.UnixNano()%int64(align) != 0 {
+		t.Error("Failed to align runs: ", a, b)
+	}
+}
+
+func Test_Worker_SetRandSource(t *testing.T) {
+	if err := NewWorker().SetRandSource(nil); err == nil {
+		t.Error("Failed to detect nil random source")
+	}
+
+	a, err := tk.Create(time.Minute, time.Second, 0, outer("hello"))
+
+	if err != nil {
+		t.Error("Failed to create task: ", err)
+	}
+
+	a.SetJitter(time.Second * 10)
+	a.SetSplay(time.Second * 10)
+
+	w1, w2 := NewWorker(), NewWorker()
+	w1.SetRandSource(rand.NewSource(42))
+	w2.SetRandSource(rand.NewSource(42))
+
+	for i := 0; i < 10; i++ {
+		o1, o2 := w1.offset("job", a, time.Time{}), w2.offset("job", a, time.Time{})
+
+		if o1 != o2 {
+			t.Error("Offsets differ for the same seed: ", o1, o2)
+		}
+
+		if o1 < -time.Second*10 || o1 >= time.Second*20 {
+			t.Error("Offset out of range: ", o1)
+		}
+	}
+}
+
+func Test_Worker_StartSplay(t *testing.T) {
+	worker := NewWorker()
+	name := "splayed"
+	splay := time.Millisecond * 100
+
+	a, err := tk.CreateOnceAfter(0, time.Second, func(ctx context.Context) error {
+		return nil
+	})
+
+	if err != nil {
+		t.Error("Failed to create task: ", err)
+	}
+
+	a.SetSplay(splay)
+
+	if err := worker.Add(a, name); err != nil {
+		t.Error("Failed to add task to worker: ", err)
+	}
+
+	start := time.Now()
+
+	if err := worker.Start(name); err != nil {
+		t.Error("Failed to start worker: ", err)
+	}
+
+	time.Sleep(splay + time.Millisecond*50)
+
+	r, err := worker.LastRun(name, Success)
+
+	if err != nil {
+		t.Fatal("Failed to get last run: ", err)
+	}
+
+	if d := r.PlannedStart.Sub(start); d < a.Splay(name) || d > a.Splay(name)+time.Millisecond*20 {
+		t.Error("Failed to apply splay: ", d, a.Splay(name))
+	}
+}
+
+func Test_Worker_PeriodicSplay(t *testing.T) {
+	worker := NewWorker()
+	period := time.Millisecond * 100
+
+	a, err := tk.Create(period, time.Second, 0, func(ctx context.Context) error {
+		time.Sleep(time.Millisecond * 40)
+
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	a.SetSplay(period)
+	a.SetJitter(time.Millisecond * 5)
+
+	// splay and run of job last longer than period
+	name := "splayed"
+	for i := 0; a.Splay(name)+time.Millisecond*40 <= period; i++ {
+		name = fmt.Sprintf("splayed-%d", i)
+	}
+
+	worker.Add(a, name)
+
+	if err := worker.Start(name); err != nil {
+		t.Error("Failed to start worker: ", err)
+	}
+
+	time.Sleep(period*5 + a.Splay(name))
+	worker.Stop(name)
+
+	runs, _ := worker.History(name, HistoryFilter{})
+	if len(runs) < 4 {
+		t.Fatal("Wrong number of runs: ", len(runs))
+	}
+
+	for i, r := range runs {
+		if r.CatchUp {
+			t.Error("Run with splay is caught up: ", r.RunID)
+		}
+
+		if i > 0 {
+			if d := runs[i-1].Start.Sub(r.Start); d < period-period/4 || d > period+period/4 {
+				t.Error("Failed to keep splay of periodic runs: ", d)
+			}
+		}
+	}
+}
+
+func Test_Worker_GetTaskChangePayload(t *testing.T) {
+	worker := NewWorker()
+	name := "typed"
+	got := make(chan string, 1)
+
+	a, err := tk.CreateTyped(time.Second, time.Second, 0, "hello", func(ctx context.Context, s string) error {
+		got <- s
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	a.SetMaxRuns(1)
+
+	if err := worker.Add(a.Task, name); err != nil {
+		t.Fatal("Failed to add task to worker: ", err)
+	}
+
+	if _, err := worker.GetTask(name + "1"); err == nil {
+		t.Error("Failed to detect missing job")
+	}
+
+	task, err := worker.GetTask(name)
+
+	if err != nil {
+		t.Fatal("Failed to get task: ", err)
+	}
+
+	if err := task.SetPayloadJSON([]byte(`"changed"`)); err != nil {
+		t.Fatal("Failed to set payload: ", err)
+	}
+
+	if err := worker.ChangeTask(name, task); err != nil {
+		t.Fatal("Failed to change task: ", err)
+	}
+
+	if err := worker.Start(name); err != nil {
+		t.Fatal("Failed to start worker: ", err)
+	}
+
+	select {
+	case s := <-got:
+		if s != "changed" {
+			t.Error("Failed to change payload: ", s)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Job didn't run")
+	}
+}
+
+func Test_Worker_CommandHistory(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("No shell")
+	}
+
+	worker := NewWorker()
+	name := "command"
+
+	a, err := tk.CreateCommand(time.Second, time.Second, 0, tk.Command{Path: "sh", Args: []string{"-c", "echo done; exit 2"}})
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	a.SetMaxRuns(1)
+
+	if err := worker.Add(a, name); err != nil {
+		t.Fatal("Failed to add task to worker: ", err)
+	}
+
+	if err := worker.Start(name); err != nil {
+		t.Fatal("Failed to start worker: ", err)
+	}
+
+	time.Sleep(time.Millisecond * 300)
+
+	r, err := worker.LastRun(name, Failure)
+
+	if err != nil {
+		t.Fatal("Failed to get last run: ", err)
+	}
+
+	res, ok := r.Result.(tk.CommandResult)
+
+	if !ok || res.ExitCode != 2 || res.Stdout != "done\n" {
+		t.Error("Failed to record command output in history: ", r.Result)
+	}
+}
+
+func Test_Worker_CommandTimeoutHistory(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("No shell")
+	}
+
+	worker := NewWorker()
+	name := "command"
+
+	a, err := tk.CreateCommand(time.Second, time.Millisecond*100, 0, tk.Command{Path: "sh", Args: []string{"-c", "echo started; echo failing >&2; sleep 5"}})
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	if a.GetWaitDelay() != tk.CommandWaitDelay {
+		t.Error("Wrong wait delay of command: ", a.GetWaitDelay())
+	}
+
+	a.SetMaxRuns(1)
+	worker.Add(a, name)
+
+	if err := worker.Start(name); err != nil {
+		t.Fatal("Failed to start worker: ", err)
+	}
+
+	time.Sleep(time.Millisecond * 500)
+
+	r, err := worker.LastRun(name, Timeout)
+
+	if err != nil {
+		t.Fatal("Failed to get last run: ", err)
+	}
+
+	res, ok := r.Result.(tk.CommandResult)
+
+	if !ok || res.Stdout != "started\n" || res.Stderr != "failing\n" {
+		t.Error("Failed to record output of timed out command in history: ", r.Result)
+	}
+}
+
+func Test_Worker_HTTPHistory(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer s.Close()
+
+	worker := NewWorker()
+	name := "probe"
+
+	a, err := tk.CreateHTTP(time.Second, time.Second, 0, tk.HTTPRequest{URL: s.URL})
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	a.SetMaxRuns(1)
+
+	if err := worker.Add(a, name); err != nil {
+		t.Fatal("Failed to add task to worker: ", err)
+	}
+
+	if err := worker.Start(name); err != nil {
+		t.Fatal("Failed to start worker: ", err)
+	}
+
+	time.Sleep(time.Millisecond * 200)
+
+	r, err := worker.LastRun(name, Failure)
+
+	if err != nil {
+		t.Fatal("Failed to get last run: ", err)
+	}
+
+	res, ok := r.Result.(tk.HTTPResult)
+
+	if !ok || res.Status != http.StatusServiceUnavailable || res.Latency <= 0 {
+		t.Error("Failed to record status and latency in history: ", r.Result)
+	}
+}
+
+func Test_Worker_StartMisfire(t *testing.T) {
+	worker := NewWorker()
+	name := "overrun"
+	catchUps := make(chan bool, 100)
+	first := true
+
+	a, err := tk.Create(time.Millisecond*20, time.Second, 0, func(ctx context.Context) error {
+		catchUps <- tk.IsCatchUp(ctx)
+		if first {
+			first = false
+			time.Sleep(time.Millisecond * 110)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	a.SetMisfire(tk.FireAll)
+	a.SetMisfireLimit(3)
+	a.SetMaxRuns(6)
+
+	if err := worker.Add(a, name); err != nil {
+		t.Fatal("Failed to add task to worker: ", err)
+	}
+
+	if err := worker.Start(name); err != nil {
+		t.Error("Failed to start worker: ", err)
+	}
+
+	time.Sleep(time.Millisecond * 250)
+
+	runs, _ := worker.History(name, HistoryFilter{})
+	if len(runs) != 6 {
+		t.Fatal("Wrong number of runs: ", len(runs))
+	}
+
+	flags := []bool{}
+	for i := len(runs) - 1; i >= 0; i-- {
+		flags = append(flags, runs[i].CatchUp)
+		if ctxFlag := <-catchUps; ctxFlag != runs[i].CatchUp {
+			t.Error("Catch-up flag of run context doesn't match history: ", i)
+		}
+	}
+
+	if fmt.Sprint(flags) != "[false true true true false false]" {
+		t.Error("Wrong catch-up runs: ", flags)
+	}
+}
+
+func Test_Worker_StartWindows(t *testing.T) {
+	worker := NewWorker()
+	runs := make(chan string, 10)
+
+	blackout, err := tk.Between(time.Now(), time.Now().Add(time.Millisecond*80))
+	if err != nil {
+		t.Fatal("Failed to create window: ", err)
+	}
+
+	for _, action := range []tk.WindowAction{tk.WindowDefer, tk.WindowSkip} {
+		name := action.String()
+		a, err := tk.CreateOnceAfter(time.Millisecond*10, time.Second, func(ctx context.Context) error {
+			runs <- name
+			return nil
+		})
+
+		if err != nil {
+			t.Fatal("Failed to create task: ", err)
+		}
+
+		a.AddBlockedWindow(blackout)
+		a.SetWindowAction(action)
+
+		if err := worker.Add(a, name); err != nil {
+			t.Fatal("Failed to add task to worker: ", err)
+		}
+
+		if err := worker.Start(name); err != nil {
+			t.Error("Failed to start worker: ", err)
+		}
+	}
+
+	time.Sleep(time.Millisecond * 150)
+
+	if len(runs) != 1 || <-runs != "defer" {
+		t.Fatal("Wrong runs of tasks with blocked window")
+	}
+
+	deferred, _ := worker.History("defer", HistoryFilter{})
+	if len(deferred) != 1 || deferred[0].Outcome != Success || !strings.HasPrefix(deferred[0].Reason, "Deferred to") {
+		t.Fatal("Failed to record deferred run: ", deferred)
+	}
+
+	if r := deferred[0]; r.Start.Before(r.PlannedStart.Add(time.Millisecond * 50)) {
+		t.Error("Run isn't deferred till the end of blocked window: ", r.PlannedStart, r.Start)
+	}
+
+	skipped, _ := worker.History("skip", HistoryFilter{})
+	if len(skipped) != 1 || skipped[0].Outcome != Blocked || !strings.Contains(skipped[0].Reason, "blocked window") {
+		t.Error("Failed to record skipped run: ", skipped)
+	}
+}
+
+func Test_Worker_NextRuns(t *testing.T) {
+	worker := NewWorker()
+	name := "previewed"
+
+	if _, err := worker.NextRuns(name, 3); !errors.Is(err, ErrJobNotFound) {
+		t.Error("Failed to detect missing job: ", err)
+	}
+
+	a, err := tk.Create(time.Millisecond*40, time.Second, time.Millisecond*20, func(ctx context.Context) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal("Failed to create task: ", err)
+	}
+
+	a.SetJitter(time.Millisecond * 10)
+	a.SetJitterSeed(7)
+	a.SetMaxRuns(4)
+
+	if err := worker.Add(a, name); err != nil {
+		t.Fatal("Failed to add task to worker: ", err)
+	}
+
+	if _, err := worker.NextRuns(name, 0); err == nil {
+		t.Error("Failed to detect wrong number of runs")
+	}
+
+	before := time.Now()
+	if fires, err := worker.NextRuns(name, 10); err != nil || len(fires) != 4 || fires[0].At.Before(before.Add(time.Millisecond*10)) || !fires[0].Exact {
+		t.Error("Failed to preview runs of job which isn't started: ", fires, err)
+	}
+
+	if err := worker.Start(name); err != nil {
+		t.Error("Failed to start worker: ", err)
+	}
+
+	time.Sleep(time.Millisecond * 40)
+
+	fires, err := worker.NextRuns(name, 10)
+	if err != nil || len(fires) != 3 {
+		t.Fatal("Failed to preview runs of started job: ", fires, err)
+	}
+
+	time.Sleep(time.Until(fires[2].At) + time.Millisecond*50)
+
+	runs, _ := worker.History(name, HistoryFilter{})
+	if len(runs) != 4 {
+		t.Fatal("Wrong number of runs: ", len(runs))
+	}
+
+	for i, f := range fires {
+		if d := runs[2-i].Start.Sub(f.At); d < 0 || d > time.Millisecond*20 {
+			t.Error("Run doesn't start at previewed time: ", f.At, runs[2-i].Start)
+		}
+	}
+
+	if fires, err := worker.NextRuns(name, 10); err != nil || len(fires) != 4 {
+		t.Error("Failed to preview runs of completed job: ", fires, err)
+	}
+}
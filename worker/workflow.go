@@ -0,0 +1,410 @@
+package worker
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+type StepStatus string
+
+const (
+	Pending   StepStatus = "pending"
+	Running   StepStatus = "running"
+	Succeeded StepStatus = "succeeded"
+	Failed    StepStatus = "failed"
+	Skipped   StepStatus = "skipped, upstream step failed"
+)
+
+// number of finished runs kept for each workflow
+const workflowRunsKept = 100
+
+// graph of jobs, every step runs after all its upstream steps succeeded
+type workflow struct {
+	deps  map[string][]string
+	down  map[string][]string
+	order []string
+	runs  []string
+	count int
+}
+
+// status of one run of workflow
+type WorkflowRun struct {
+	ID       string
+	Workflow string
+	Status   StepStatus
+	Start    time.Time
+	End      time.Time
+	Steps    map[string]StepStatus
+	Runs     map[string]RunRecord
+}
+
+type workflowRun struct {
+	sync.Mutex
+	WorkflowRun
+}
+
+// creates workflow from steps and their upstream steps, checks that graph has no cycles
+func newWorkflow(deps map[string][]string) (*workflow, error) {
+	wf := &workflow{deps: make(map[string][]string), down: make(map[string][]string)}
+
+	for s, ups := range deps {
+		wf.deps[s] = append(wf.deps[s], ups...)
+
+		for _, u := range ups {
+			if u == s {
+				return nil, fmt.Errorf("Step %v depends on itself", s)
+			}
+
+			if _, ok := wf.deps[u]; !ok {
+				wf.deps[u] = nil
+			}
+
+			wf.down[u] = append(wf.down[u], s)
+		}
+	}
+
+	// Kahn's algorithm, steps left with upstream steps form cycles
+	left := make(map[string]int)
+	ready := []string{}
+
+	for s, ups := range wf.deps {
+		left[s] = len(ups)
+		if len(ups) == 0 {
+			ready = append(ready, s)
+		}
+	}
+
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		s := ready[0]
+		ready = ready[1:]
+		wf.order = append(wf.order, s)
+
+		for _, d := range wf.down[s] {
+			left[d]--
+			if left[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+	}
+
+	if len(wf.order) != len(wf.deps) {
+		cycle := []string{}
+		for s, l := range left {
+			if l > 0 {
+				cycle = append(cycle, s)
+			}
+		}
+		sort.Strings(cycle)
+
+		return nil, fmt.Errorf("Workflow has cycle between steps %v", cycle)
+	}
+
+	return wf, nil
+}
+
+// adds workflow of jobs from job pool, deps maps every step to its upstream steps;
+// besides runs started by RunWorkflow, successful scheduled run of step triggers run of steps downstream of it
+func (w *worker) AddWorkflow(name string, deps map[string][]string) error {
+	if len(deps) == 0 {
+		return fmt.Errorf("No steps provided")
+	}
+
+	wf, err := newWorkflow(deps)
+	if err != nil {
+		return err
+	}
+
+	defer w.Unlock()
+	w.Lock()
+
+	if _, ok := w.workflows[name]; ok {
+		return fmt.Errorf("Workflow with name %v already exist", name)
+	}
+
+	for _, s := range wf.order {
+		if _, ok := w.jobs[s]; !ok {
+			return jobError(s, ErrJobNotFound)
+		}
+	}
+
+	w.workflows[name] = wf
+
+	return nil
+}
+
+// deletes workflow and its runs
+func (w *worker) DeleteWorkflow(name string) error {
+	defer w.Unlock()
+	w.Lock()
+
+	wf, ok := w.workflows[name]
+	if !ok {
+		return fmt.Errorf("No workflow with name %v", name)
+	}
+
+	for _, id := range wf.runs {
+		delete(w.workflowRuns, id)
+	}
+
+	delete(w.workflows, name)
+
+	return nil
+}
+
+// starts run of workflow, returns its ID
+func (w *worker) RunWorkflow(name string) (string, error) {
+	w.Lock()
+
+	wf, ok := w.workflows[name]
+	if !ok {
+		w.Unlock()
+		return "", fmt.Errorf("No workflow with name %v", name)
+	}
+
+	r := w.newWorkflowRun(name, wf, "", RunRecord{})
+	w.Unlock()
+
+	go w.runWorkflow(wf, r)
+
+	return r.ID, nil
+}
+
+// returns IDs of kept runs of workflow from the oldest one, including runs triggered by scheduled runs of its steps
+func (w *worker) WorkflowRuns(name string) ([]string, error) {
+	defer w.Unlock()
+	w.Lock()
+
+	wf, ok := w.workflows[name]
+	if !ok {
+		return nil, fmt.Errorf("No workflow with name %v", name)
+	}
+
+	return append([]string(nil), wf.runs...), nil
+}
+
+// starts runs of workflows triggered by successful scheduled run rec of job n: run of every workflow where n has downstream steps
+// runs them and steps downstream of them
+func (w *worker) triggerWorkflows(n string, rec RunRecord) {
+	w.Lock()
+	names := make([]string, 0, len(w.workflows))
+	for name, wf := range w.workflows {
+		if len(wf.down[n]) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	wfs := []*workflow{}
+	runs := []*workflowRun{}
+	for _, name := range names {
+		wfs = append(wfs, w.workflows[name])
+		runs = append(runs, w.newWorkflowRun(name, w.workflows[name], n, rec))
+	}
+	w.Unlock()
+
+	for i, r := range runs {
+		go w.runWorkflow(wfs[i], r)
+	}
+}
+
+// returns name of workflow which has job n as its step, empty if there is none; w must be locked
+func (w *worker) workflowOf(n string) string {
+	names := []string{}
+	for name, wf := range w.workflows {
+		if _, ok := wf.deps[n]; ok {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+
+	return names[0]
+}
+
+// creates run of workflow wf with name, run of every step if trigger is empty; otherwise trigger has succeeded with run rec
+// and run has steps downstream of it, their upstream steps outside of run are taken with status of their last runs; w must be locked
+func (w *worker) newWorkflowRun(name string, wf *workflow, trigger string, rec RunRecord) *workflowRun {
+	wf.count++
+	r := &workflowRun{WorkflowRun: WorkflowRun{
+		ID:       fmt.Sprintf("%v-%d", name, wf.count),
+		Workflow: name,
+		Status:   Running,
+		Start:    time.Now(),
+		Steps:    make(map[string]StepStatus),
+		Runs:     make(map[string]RunRecord),
+	}}
+
+	if trigger == "" {
+		for _, s := range wf.order {
+			r.Steps[s] = Pending
+		}
+	} else {
+		r.Steps[trigger], r.Runs[trigger] = Succeeded, rec
+		downstream(wf, r, trigger)
+
+		for _, s := range wf.order {
+			if r.Steps[s] != Pending {
+				continue
+			}
+
+			for _, u := range wf.deps[s] {
+				if _, ok := r.Steps[u]; ok {
+					continue
+				}
+
+				r.Steps[u] = Failed
+				if j, ok := w.jobs[u]; ok {
+					j.Lock()
+					if last := j.history.query(HistoryFilter{Limit: 1}, time.Now()); len(last) > 0 {
+						r.Runs[u] = last[0]
+						if last[0].Outcome == Success {
+							r.Steps[u] = Succeeded
+						}
+					}
+					j.Unlock()
+				}
+			}
+		}
+
+		for _, s := range wf.order {
+			if r.Steps[s] == Failed {
+				skip(wf, r, s)
+			}
+		}
+	}
+
+	wf.runs = append(wf.runs, r.ID)
+	if len(wf.runs) > workflowRunsKept {
+		delete(w.workflowRuns, wf.runs[0])
+		wf.runs = wf.runs[1:]
+	}
+
+	w.workflowRuns[r.ID] = r
+
+	return r
+}
+
+// marks steps downstream of step s as pending, run must be locked
+func downstream(wf *workflow, r *workflowRun, s string) {
+	for _, d := range wf.down[s] {
+		if _, ok := r.Steps[d]; !ok {
+			r.Steps[d] = Pending
+			downstream(wf, r, d)
+		}
+	}
+}
+
+// returns status of workflow run by its ID
+func (w *worker) WorkflowStatus(id string) (WorkflowRun, error) {
+	w.Lock()
+	r, ok := w.workflowRuns[id]
+	w.Unlock()
+
+	if !ok {
+		return WorkflowRun{}, fmt.Errorf("No workflow run with ID %v", id)
+	}
+
+	defer r.Unlock()
+	r.Lock()
+
+	res := r.WorkflowRun
+	res.Steps = make(map[string]StepStatus)
+	res.Runs = make(map[string]RunRecord)
+
+	for s, st := range r.Steps {
+		res.Steps[s] = st
+	}
+
+	for s, rec := range r.Runs {
+		res.Runs[s] = rec
+	}
+
+	return res, nil
+}
+
+type stepResult struct {
+	step string
+	rec  RunRecord
+	err  error
+}
+
+// controls run of workflow: runs steps whose upstream steps succeeded, skips steps downstream of failed ones
+func (w *worker) runWorkflow(wf *workflow, r *workflowRun) {
+	results := make(chan stepResult)
+	running := 0
+
+	for {
+		r.Lock()
+		for _, s := range wf.order {
+			if st, ok := r.Steps[s]; !ok || st != Pending {
+				continue
+			}
+
+			ready := true
+			for _, u := range wf.deps[s] {
+				if r.Steps[u] != Succeeded {
+					ready = false
+					break
+				}
+			}
+
+			if !ready {
+				continue
+			}
+
+			r.Steps[s] = Running
+			running++
+
+			go func(s string) {
+				rec, err := w.runOnce(s, nil, "")
+				results <- stepResult{step: s, rec: rec, err: err}
+			}(s)
+		}
+		r.Unlock()
+
+		if running == 0 {
+			break
+		}
+
+		res := <-results
+		running--
+
+		r.Lock()
+		r.Runs[res.step] = res.rec
+
+		if res.err == nil && res.rec.Outcome == Success {
+			r.Steps[res.step] = Succeeded
+		} else {
+			r.Steps[res.step] = Failed
+			skip(wf, r, res.step)
+		}
+		r.Unlock()
+	}
+
+	r.Lock()
+	r.End = time.Now()
+	r.Status = Succeeded
+
+	for _, st := range r.Steps {
+		if st != Succeeded {
+			r.Status = Failed
+		}
+	}
+	r.Unlock()
+}
+
+// marks pending steps downstream of step s as skipped, run must be locked
+func skip(wf *workflow, r *workflowRun, s string) {
+	for _, d := range wf.down[s] {
+		if r.Steps[d] == Pending {
+			r.Steps[d] = Skipped
+			skip(wf, r, d)
+		}
+	}
+}
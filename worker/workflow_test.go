@@ -0,0 +1,276 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	tk "github.com/vslchnk/goscheduler/task"
+)
+
+// adds jobs which append their names to order, jobs in failing return error
+func addSteps(t *testing.T, worker *worker, order *[]string, mu *sync.Mutex, failing map[string]bool, names ...string) {
+	for _, n := range names {
+		n := n
+
+		a, err := tk.Create(time.Second, time.Second, 0, func(ctx context.Context) error {
+			time.Sleep(time.Millisecond * 10)
+
+			mu.Lock()
+			*order = append(*order, n)
+			mu.Unlock()
+
+			if failing[n] {
+				return fmt.Errorf("%v failed", n)
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			t.Fatal("Failed to create task: ", err)
+		}
+
+		if err := worker.Add(a, n); err != nil {
+			t.Fatal("Failed to add task to worker: ", err)
+		}
+	}
+}
+
+// waits until workflow run is finished
+func waitWorkflow(t *testing.T, worker *worker, id string) WorkflowRun {
+	for i := 0; i < 100; i++ {
+		r, err := worker.WorkflowStatus(id)
+
+		if err != nil {
+			t.Fatal("Failed to get workflow status: ", err)
+		}
+
+		if r.Status != Running {
+			return r
+		}
+
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	t.Fatal("Workflow run isn't finished")
+
+	return WorkflowRun{}
+}
+
+func Test_Workflow_AddWithCycle(t *testing.T) {
+	worker := NewWorker()
+	order, mu := []string{}, &sync.Mutex{}
+	addSteps(t, worker, &order, mu, nil, "a", "b", "c")
+
+	if err := worker.AddWorkflow("cycle", map[string][]string{"a": {"c"}, "b": {"a"}, "c": {"b"}}); err == nil {
+		t.Error("Failed to detect cycle")
+	}
+
+	if err := worker.AddWorkflow("self", map[string][]string{"a": {"a"}}); err == nil {
+		t.Error("Failed to detect step depending on itself")
+	}
+
+	if err := worker.AddWorkflow("missing", map[string][]string{"a": {"d"}}); err == nil {
+		t.Error("Failed to detect missing job")
+	}
+
+	if err := worker.AddWorkflow("empty", nil); err == nil {
+		t.Error("Failed to detect empty workflow")
+	}
+
+	if err := worker.AddWorkflow("ok", map[string][]string{"b": {"a"}}); err != nil {
+		t.Error("Failed to add workflow: ", err)
+	}
+
+	if err := worker.AddWorkflow("ok", map[string][]string{"c": {"a"}}); err == nil {
+		t.Error("Failed to detect duplicated workflow")
+	}
+}
+
+func Test_Workflow_FanOutFanIn(t *testing.T) {
+	worker := NewWorker()
+	order, mu := []string{}, &sync.Mutex{}
+	addSteps(t, worker, &order, mu, nil, "extract", "left", "right", "load")
+
+	err := worker.AddWorkflow("etl", map[string][]string{
+		"left":  {"extract"},
+		"right": {"extract"},
+		"load":  {"left", "right"},
+	})
+
+	if err != nil {
+		t.Fatal("Failed to add workflow: ", err)
+	}
+
+	id, err := worker.RunWorkflow("etl")
+
+	if err != nil {
+		t.Fatal("Failed to run workflow: ", err)
+	}
+
+	r := waitWorkflow(t, worker, id)
+
+	if r.Status != Succeeded || r.Workflow != "etl" || r.End.Before(r.Start) {
+		t.Error("Wrong workflow run: ", r)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(order) != 4 || order[0] != "extract" || order[3] != "load" {
+		t.Error("Wrong order of steps: ", order)
+	}
+
+	for s, st := range r.Steps {
+		if st != Succeeded || r.Runs[s].Outcome != Success {
+			t.Error("Wrong status of step ", s, ": ", st, r.Runs[s])
+		}
+	}
+
+	if _, err := worker.LastRun("load", Success); err != nil {
+		t.Error("Failed to record step in job history: ", err)
+	}
+}
+
+func Test_Workflow_SkipAfterFailure(t *testing.T) {
+	worker := NewWorker()
+	order, mu := []string{}, &sync.Mutex{}
+	addSteps(t, worker, &order, mu, map[string]bool{"b": true}, "a", "b", "c", "d", "e")
+
+	err := worker.AddWorkflow("chain", map[string][]string{
+		"b": {"a"},
+		"c": {"b"},
+		"d": {"c"},
+		"e": {"a"},
+	})
+
+	if err != nil {
+		t.Fatal("Failed to add workflow: ", err)
+	}
+
+	id, err := worker.RunWorkflow("chain")
+
+	if err != nil {
+		t.Fatal("Failed to run workflow: ", err)
+	}
+
+	r := waitWorkflow(t, worker, id)
+
+	want := map[string]StepStatus{"a": Succeeded, "b": Failed, "c": Skipped, "d": Skipped, "e": Succeeded}
+
+	for s, st := range want {
+		if r.Steps[s] != st {
+			t.Error("Wrong status of step ", s, ": ", r.Steps[s])
+		}
+	}
+
+	if r.Status != Failed || r.Runs["b"].Err != "b failed" {
+		t.Error("Wrong workflow run: ", r)
+	}
+}
+
+func Test_Workflow_RunError(t *testing.T) {
+	worker := NewWorker()
+	order, mu := []string{}, &sync.Mutex{}
+	addSteps(t, worker, &order, mu, nil, "a")
+
+	if _, err := worker.RunWorkflow("missing"); err == nil {
+		t.Error("Failed to detect missing workflow")
+	}
+
+	if _, err := worker.WorkflowStatus("missing-1"); err == nil {
+		t.Error("Failed to detect missing workflow run")
+	}
+
+	if err := worker.AddWorkflow("one", map[string][]string{"a": nil}); err != nil {
+		t.Fatal("Failed to add workflow: ", err)
+	}
+
+	id, err := worker.RunWorkflow("one")
+
+	if err != nil {
+		t.Fatal("Failed to run workflow: ", err)
+	}
+
+	waitWorkflow(t, worker, id)
+
+	if err := worker.DeleteWorkflow("one"); err != nil {
+		t.Error("Failed to delete workflow: ", err)
+	}
+
+	if _, err := worker.WorkflowStatus(id); err == nil {
+		t.Error("Failed to delete workflow runs")
+	}
+
+	if err := worker.DeleteWorkflow("one"); err == nil {
+		t.Error("Failed to detect missing workflow")
+	}
+}
+
+func Test_Workflow_Triggered(t *testing.T) {
+	worker := NewWorker()
+	order, mu := []string{}, &sync.Mutex{}
+	addSteps(t, worker, &order, mu, nil, "extract", "left", "right", "load", "audit", "report")
+
+	worker.AddWorkflow("etl", map[string][]string{
+		"left":  {"extract"},
+		"right": {"extract"},
+		"load":  {"left", "right"},
+	})
+
+	// audit never runs, so report is skipped
+	worker.AddWorkflow("report", map[string][]string{
+		"report": {"extract", "audit"},
+	})
+
+	if err := worker.Start("extract"); err != nil {
+		t.Fatal("Failed to start worker: ", err)
+	}
+
+	time.Sleep(time.Millisecond * 100)
+	worker.Stop("extract")
+
+	ids, err := worker.WorkflowRuns("etl")
+	if err != nil || len(ids) != 1 {
+		t.Fatal("Failed to trigger workflow by scheduled run: ", ids, err)
+	}
+
+	r := waitWorkflow(t, worker, ids[0])
+	if r.Status != Succeeded || len(r.Steps) != 4 || r.Runs["extract"].Outcome != Success {
+		t.Error("Wrong triggered workflow run: ", r)
+	}
+
+	ids, _ = worker.WorkflowRuns("report")
+	if len(ids) != 1 {
+		t.Fatal("Failed to trigger workflow by scheduled run: ", ids)
+	}
+
+	r = waitWorkflow(t, worker, ids[0])
+	if r.Status != Failed || r.Steps["audit"] != Failed || r.Steps["report"] != Skipped {
+		t.Error("Failed to skip step with failed upstream step outside of run: ", r)
+	}
+
+	mu.Lock()
+	if len(order) != 4 || order[0] != "extract" || order[3] != "load" {
+		t.Error("Wrong order of steps: ", order)
+	}
+	mu.Unlock()
+
+	if err := worker.Delete("left"); !errors.Is(err, ErrJobInWorkflow) {
+		t.Error("Failed to detect deleting step of workflow: ", err)
+	}
+
+	worker.DeleteWorkflow("etl")
+
+	if err := worker.Delete("left"); err != nil {
+		t.Error("Failed to delete job: ", err)
+	}
+
+	if _, err := worker.WorkflowRuns("etl"); err == nil {
+		t.Error("Failed to detect missing workflow")
+	}
+}